@@ -0,0 +1,60 @@
+package xpool
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// AutoReleaser is an [io.Closer] that put an object back to the pool on Close, see [AutoRelease].
+type AutoReleaser[T any] struct {
+	object   T
+	pool     Putter[T]
+	released uint32
+}
+
+var _ io.Closer = (*AutoReleaser[any])(nil)
+
+// AutoRelease returns an [io.Closer] that put the object back to the pool exactly once, on the first Close.
+// Useful to pass pooled objects through APIs that only know how to close things.
+// Will panic if pool is nil.
+func AutoRelease[T any](pool Putter[T], object T) *AutoReleaser[T] {
+	if pool == nil {
+		panic("argument 'pool' must not be nil")
+	}
+
+	return &AutoReleaser[T]{object: object, pool: pool}
+}
+
+// Value returns the object. It must not be used after Close.
+func (r *AutoReleaser[T]) Value() T {
+	return r.object
+}
+
+// Close put the object back to the pool. Only the first call has effect, it is safe to call it several times.
+// It always returns nil.
+func (r *AutoReleaser[T]) Close() error {
+	if atomic.CompareAndSwapUint32(&r.released, 0, 1) {
+		r.pool.Put(r.object)
+	}
+
+	return nil
+}
+
+// AutoReleaseReader returns an [io.ReadCloser] that reads from the object,
+// and put it back to the pool on the first Close, see [AutoRelease].
+func AutoReleaseReader[T io.Reader](pool Putter[T], object T) io.ReadCloser {
+	return struct {
+		io.Reader
+		io.Closer
+	}{object, AutoRelease(pool, object)}
+}
+
+// AutoReleaseWriter returns an [io.WriteCloser] that writes to the object,
+// and put it back to the pool on the first Close, see [AutoRelease].
+// Be careful, buffered writers must be flushed before Close, like by the resetter.
+func AutoReleaseWriter[T io.Writer](pool Putter[T], object T) io.WriteCloser {
+	return struct {
+		io.Writer
+		io.Closer
+	}{object, AutoRelease(pool, object)}
+}
@@ -0,0 +1,65 @@
+package xpool_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestAutoRelease(t *testing.T) {
+	t.Parallel()
+
+	parent := new(recordingPool)
+
+	buffer := parent.Get()
+
+	closer := xpool.AutoRelease[*bytes.Buffer](parent, buffer)
+	assert.Same(t, buffer, closer.Value())
+
+	require.NoError(t, closer.Close())
+	require.NoError(t, closer.Close()) // idempotent
+
+	require.Len(t, parent.puts, 1, "must put back exactly once")
+	assert.Same(t, buffer, parent.puts[0])
+}
+
+func TestAutoReleaseReader(t *testing.T) {
+	t.Parallel()
+
+	parent := new(recordingPool)
+
+	buffer := parent.Get()
+	buffer.WriteString("payload")
+
+	rc := xpool.AutoReleaseReader[*bytes.Buffer](parent, buffer)
+
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(content))
+
+	require.NoError(t, rc.Close())
+	assert.Len(t, parent.puts, 1)
+}
+
+func TestAutoReleaseWriter(t *testing.T) {
+	t.Parallel()
+
+	parent := new(recordingPool)
+
+	buffer := parent.Get()
+
+	wc := xpool.AutoReleaseWriter[*bytes.Buffer](parent, buffer)
+
+	_, err := io.Copy(wc, strings.NewReader("payload"))
+	require.NoError(t, err)
+	require.NoError(t, wc.Close())
+
+	assert.Equal(t, "payload", buffer.String())
+	assert.Len(t, parent.puts, 1)
+}
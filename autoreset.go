@@ -0,0 +1,101 @@
+package xpool
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// AutoResetTag is the struct tag that marks the fields kept by the resetter of [NewWithAutoReset],
+// like `xpool:"keep"`.
+const AutoResetTag = "xpool"
+
+// NewWithAutoReset is an alternative constructor of an [Pool] for a given generic type T.
+// T must be a pointer to struct, before put the object back to object pool we will set
+// all fields to their zero values, without a hand-written resetter that may drift when fields are added.
+// The fields tagged with `xpool:"keep"`, or named via [WithKeepFields], are kept as they are,
+// like a buffer allocated by the constructor.
+// Will panic if T is not a pointer to struct, or if a field named via [WithKeepFields] does not exist.
+// The behavior can be customized via [Option].
+func NewWithAutoReset[T any](
+	ctor func() T,
+	opts ...Option[T],
+) Pool[T] {
+	resetter := newAutoResetter[T](buildOptions(opts).keepFields)
+
+	return newResettablePool(ctor, func(object T) error {
+		resetter.reset(object)
+
+		return nil
+	}, opts)
+}
+
+// autoResetter zeroes the fields of a struct via reflection, see [NewWithAutoReset].
+type autoResetter[T any] struct {
+	// fields holds the indexes of the fields to reset, or nil if all fields must be reset.
+	fields []int
+}
+
+func newAutoResetter[T any](keepFields []string) *autoResetter[T] {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("type %s must be a pointer to struct", typ))
+	}
+
+	typ = typ.Elem()
+
+	keep := make(map[string]bool, len(keepFields))
+
+	for _, name := range keepFields {
+		field, ok := typ.FieldByName(name)
+		if !ok {
+			panic(fmt.Sprintf("field %q not found on type %s", name, typ))
+		}
+
+		if len(field.Index) != 1 {
+			panic(fmt.Sprintf("field %q is promoted from an embedded struct on type %s", name, typ))
+		}
+
+		keep[name] = true
+	}
+
+	fields := make([]int, 0, typ.NumField())
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if keep[field.Name] || field.Tag.Get(AutoResetTag) == "keep" {
+			continue
+		}
+
+		fields = append(fields, i)
+	}
+
+	if len(fields) == typ.NumField() {
+		fields = nil
+	}
+
+	return &autoResetter[T]{fields: fields}
+}
+
+func (r *autoResetter[T]) reset(object T) {
+	value := reflect.ValueOf(object)
+	if value.IsNil() {
+		return
+	}
+
+	value = value.Elem()
+
+	if r.fields == nil {
+		value.Set(reflect.Zero(value.Type()))
+
+		return
+	}
+
+	for _, i := range r.fields {
+		field := value.Field(i)
+
+		// the unexported fields can not be set directly.
+		field = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+		field.Set(reflect.Zero(field.Type()))
+	}
+}
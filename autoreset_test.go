@@ -0,0 +1,101 @@
+package xpool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+type autoResetRecord struct {
+	ID      int
+	Tags    map[string]string
+	name    string
+	scratch []byte `xpool:"keep"`
+	Owner   string
+}
+
+type autoResetEmbedded struct {
+	Owner string
+}
+
+type autoResetOuter struct {
+	autoResetEmbedded
+	ID int
+}
+
+func TestNewWithAutoReset(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithAutoReset(func() *autoResetRecord {
+		return new(autoResetRecord)
+	})
+
+	xpooltest.AssertResetZeroes(t, pool, func(r *autoResetRecord) {
+		r.ID = 42
+		r.Tags = map[string]string{"k": "v"}
+		r.name = "name"
+		r.Owner = "owner"
+	})
+}
+
+func TestNewWithAutoResetKeep(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithAutoReset(func() *autoResetRecord {
+		return &autoResetRecord{scratch: make([]byte, 0, 64)}
+	}, xpool.WithKeepFields[*autoResetRecord]("Owner"))
+
+	record := pool.Get()
+	record.ID = 42
+	record.name = "name"
+	record.scratch = append(record.scratch, "payload"...)
+	record.Owner = "owner"
+
+	pool.Put(record)
+
+	assert.Zero(t, record.ID)
+	assert.Empty(t, record.name)
+	assert.Equal(t, "payload", string(record.scratch), "must keep the tagged field")
+	assert.Equal(t, "owner", record.Owner, "must keep the named field")
+}
+
+func TestNewWithAutoResetInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.NewWithAutoReset(func() autoResetRecord {
+			return autoResetRecord{}
+		})
+	}, "must panic if T is not a pointer to struct")
+
+	assert.Panics(t, func() {
+		xpool.NewWithAutoReset(func() *autoResetRecord {
+			return new(autoResetRecord)
+		}, xpool.WithKeepFields[*autoResetRecord]("Unknown"))
+	}, "must panic if the field does not exist")
+
+	assert.PanicsWithValue(t,
+		`field "Owner" is promoted from an embedded struct on type xpool_test.autoResetOuter`,
+		func() {
+			xpool.NewWithAutoReset(func() *autoResetOuter {
+				return new(autoResetOuter)
+			}, xpool.WithKeepFields[*autoResetOuter]("Owner"))
+		}, "must panic if the field is promoted from an embedded struct")
+}
+
+func BenchmarkNewWithAutoReset(b *testing.B) {
+	pool := xpool.NewWithAutoReset(func() *autoResetRecord {
+		return new(autoResetRecord)
+	})
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		record := pool.Get()
+		record.ID = i
+		pool.Put(record)
+	}
+}
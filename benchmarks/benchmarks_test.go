@@ -0,0 +1,131 @@
+package benchmarks_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/freelist"
+	"github.com/peczenyj/xpool/ring"
+)
+
+type small struct {
+	freelist.Hook[*small]
+	data [64]byte
+}
+
+type large struct {
+	freelist.Hook[*large]
+	data [64 * 1024]byte
+}
+
+// syncPool adapts a plain sync.Pool, the type assertion is done by the caller as usual.
+type syncPool[T any] struct {
+	pool sync.Pool
+}
+
+func newSyncPool[T any](ctor func() T) *syncPool[T] {
+	p := &syncPool[T]{}
+	p.pool.New = func() any {
+		return ctor()
+	}
+
+	return p
+}
+
+func (p *syncPool[T]) Get() T {
+	object, _ := p.pool.Get().(T)
+
+	return object
+}
+
+func (p *syncPool[T]) Put(object T) {
+	p.pool.Put(object)
+}
+
+// channelPool is the usual hand-rolled bounded pool.
+type channelPool[T any] struct {
+	objects chan T
+	ctor    func() T
+}
+
+func newChannelPool[T any](capacity int, ctor func() T) *channelPool[T] {
+	return &channelPool[T]{objects: make(chan T, capacity), ctor: ctor}
+}
+
+func (p *channelPool[T]) Get() T {
+	select {
+	case object := <-p.objects:
+		return object
+	default:
+		return p.ctor()
+	}
+}
+
+func (p *channelPool[T]) Put(object T) {
+	select {
+	case p.objects <- object:
+	default:
+	}
+}
+
+const capacity = 1024
+
+var parallelisms = []int{1, 4, 16}
+
+func BenchmarkSmall(b *testing.B) {
+	benchmarkBackends(b, func() *small {
+		return new(small)
+	}, func(s *small) {
+		s.data[0]++
+	})
+}
+
+func BenchmarkLarge(b *testing.B) {
+	benchmarkBackends(b, func() *large {
+		return new(large)
+	}, func(l *large) {
+		l.data[0]++
+	})
+}
+
+func benchmarkBackends[T any, P interface {
+	*T
+	freelist.Node[P]
+}](b *testing.B, ctor func() P, use func(P)) {
+	b.Helper()
+
+	backends := []struct {
+		name    string
+		newPool func() xpool.Pool[P]
+	}{
+		{"sync", func() xpool.Pool[P] { return newSyncPool(ctor) }},
+		{"xpool", func() xpool.Pool[P] { return xpool.New(ctor) }},
+		{"hot", func() xpool.Pool[P] { return xpool.New(ctor, xpool.WithHotTier[P](capacity)) }},
+		{"channel", func() xpool.Pool[P] { return newChannelPool(capacity, ctor) }},
+		{"ring", func() xpool.Pool[P] { return ring.New(capacity, ctor) }},
+		{"freelist", func() xpool.Pool[P] { return freelist.New(ctor) }},
+	}
+
+	for _, backend := range backends {
+		for _, parallelism := range parallelisms {
+			backend, parallelism := backend, parallelism
+
+			b.Run(fmt.Sprintf("%s/p%d", backend.name, parallelism), func(b *testing.B) {
+				pool := backend.newPool()
+
+				b.ReportAllocs()
+				b.SetParallelism(parallelism)
+
+				b.RunParallel(func(pb *testing.PB) {
+					for pb.Next() {
+						object := pool.Get()
+						use(object)
+						pool.Put(object)
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Package benchmarks compares the backends offered by xpool, across contention levels and object sizes.
+// It has no code, only standard Go benchmarks:
+//
+//	go test -run=^$ -bench=. -benchmem github.com/peczenyj/xpool/benchmarks
+//
+// The backends are:
+//   - sync: a plain [sync.Pool], with the type assertion on the caller side.
+//   - xpool: [github.com/peczenyj/xpool.New], on top of [sync.Pool].
+//   - hot: [github.com/peczenyj/xpool.New] with [github.com/peczenyj/xpool.WithHotTier].
+//   - channel: a buffered channel, the usual hand-rolled bounded pool.
+//   - ring: [github.com/peczenyj/xpool/ring], a lock-free LIFO stack.
+//   - freelist: [github.com/peczenyj/xpool/freelist], an intrusive free list.
+//
+// The contention is controlled by the parallelism of [testing.B.RunParallel], multiplied by GOMAXPROCS.
+package benchmarks
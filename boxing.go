@@ -0,0 +1,63 @@
+package xpool
+
+import (
+	"reflect"
+	"sync"
+)
+
+// needsBoxing tells if store a value of type T as any allocates memory,
+// like structs, arrays, strings and slices. Pointer-shaped types, like pointers and maps,
+// and interfaces are stored as they are.
+func needsBoxing[T any]() bool {
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Interface:
+		return false
+	default:
+		return true
+	}
+}
+
+// box holds a value of type T stored on the pool.
+type box[T any] struct {
+	value T
+}
+
+// boxer stores the values of type T on reusable boxes, so Put does not allocate for value types.
+type boxer[T any] struct {
+	empty sync.Pool
+}
+
+func newBoxer[T any]() *boxer[T] {
+	if !needsBoxing[T]() {
+		return nil
+	}
+
+	return &boxer[T]{}
+}
+
+func (b *boxer[T]) wrap(object T) any {
+	bx, _ := b.empty.Get().(*box[T])
+	if bx == nil {
+		bx = new(box[T])
+	}
+
+	bx.value = object
+
+	return bx
+}
+
+func (b *boxer[T]) unwrap(value any) (T, bool) {
+	var zero T
+
+	bx, ok := value.(*box[T])
+	if !ok {
+		return zero, false
+	}
+
+	object := bx.value
+	bx.value = zero // do not retain the object
+
+	b.empty.Put(bx)
+
+	return object, true
+}
@@ -0,0 +1,42 @@
+package xpool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+type point struct {
+	x, y int
+}
+
+func TestValueTypeNoBoxingAllocations(t *testing.T) {
+	pool := xpool.New(func() point {
+		return point{}
+	})
+
+	pool.Put(pool.Get()) // warm up the boxes
+
+	allocs := testing.AllocsPerRun(100, func() {
+		p := pool.Get()
+		p.x++
+		pool.Put(p)
+	})
+
+	assert.Zero(t, allocs, "must not allocate to box the value")
+}
+
+func TestValueTypeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.New(func() [4]byte {
+		return [4]byte{}
+	}, xpool.WithHotTier[[4]byte](1)) // the sync.Pool may drop the box
+
+	pool.Put([4]byte{1, 2, 3, 4})
+
+	got := pool.Get()
+	assert.Equal(t, [4]byte{1, 2, 3, 4}, got)
+}
@@ -0,0 +1,112 @@
+// Package bufiopool offers ready-made monadic pools of [bufio.Reader] and [bufio.Writer].
+//
+// The state of each pool is the underlying [io.Reader] or [io.Writer]:
+//
+//	pool := bufiopool.NewReaderPool(4096)
+//
+//	br := pool.Get(conn) // implicit Reset(conn)
+//	defer pool.Put(br)   // implicit Reset(nil)
+//
+// Writers are flushed before put back to the pool.
+//
+// There are also helpers, like [GetReader] and [PutReader], that manage one pool per buffer size.
+package bufiopool
+
+import (
+	"bufio"
+	"io"
+	"sync"
+
+	"github.com/peczenyj/xpool/monadic"
+)
+
+const (
+	defaultBufSize    = 4096
+	minReadBufferSize = 16
+)
+
+// NewReaderPool returns a monadic pool of [bufio.Reader] with the given buffer size.
+// The behavior can be customized via [monadic.Option].
+func NewReaderPool(
+	size int,
+	opts ...monadic.Option[io.Reader, *bufio.Reader],
+) monadic.Pool[io.Reader, *bufio.Reader] {
+	return monadic.New(func() *bufio.Reader {
+		return bufio.NewReaderSize(nil, size)
+	}, opts...)
+}
+
+// NewWriterPool returns a monadic pool of [bufio.Writer] with the given buffer size.
+// The writer is flushed before put back to the pool, the flush error is ignored.
+// The behavior can be customized via [monadic.Option].
+func NewWriterPool(
+	size int,
+	opts ...monadic.Option[io.Writer, *bufio.Writer],
+) monadic.Pool[io.Writer, *bufio.Writer] {
+	flushAndReset := monadic.WithPutResetter[io.Writer](func(bw *bufio.Writer) {
+		_ = bw.Flush()
+
+		bw.Reset(nil)
+	})
+
+	return monadic.New(func() *bufio.Writer {
+		return bufio.NewWriterSize(nil, size)
+	}, append([]monadic.Option[io.Writer, *bufio.Writer]{flushAndReset}, opts...)...)
+}
+
+var (
+	readerPools sync.Map // map[int]monadic.Pool[io.Reader, *bufio.Reader]
+	writerPools sync.Map // map[int]monadic.Pool[io.Writer, *bufio.Writer]
+)
+
+// GetReader fetch a [bufio.Reader] with the given buffer size, reading from r.
+// It must be returned via [PutReader].
+func GetReader(r io.Reader, size int) *bufio.Reader {
+	return readerPool(size).Get(r)
+}
+
+// PutReader return the [bufio.Reader] to the pool of its buffer size.
+func PutReader(br *bufio.Reader) {
+	readerPool(br.Size()).Put(br)
+}
+
+// GetWriter fetch a [bufio.Writer] with the given buffer size, writing to w.
+// It must be returned via [PutWriter].
+func GetWriter(w io.Writer, size int) *bufio.Writer {
+	return writerPool(size).Get(w)
+}
+
+// PutWriter flush the [bufio.Writer] and return it to the pool of its buffer size.
+func PutWriter(bw *bufio.Writer) {
+	writerPool(bw.Size()).Put(bw)
+}
+
+func readerPool(size int) monadic.Pool[io.Reader, *bufio.Reader] {
+	// same rules of bufio.NewReaderSize, to match the size of the reader on Put.
+	if size < minReadBufferSize {
+		size = minReadBufferSize
+	}
+
+	if pool, ok := readerPools.Load(size); ok {
+		return pool.(monadic.Pool[io.Reader, *bufio.Reader])
+	}
+
+	pool, _ := readerPools.LoadOrStore(size, NewReaderPool(size))
+
+	return pool.(monadic.Pool[io.Reader, *bufio.Reader])
+}
+
+func writerPool(size int) monadic.Pool[io.Writer, *bufio.Writer] {
+	// same rules of bufio.NewWriterSize, to match the size of the writer on Put.
+	if size <= 0 {
+		size = defaultBufSize
+	}
+
+	if pool, ok := writerPools.Load(size); ok {
+		return pool.(monadic.Pool[io.Writer, *bufio.Writer])
+	}
+
+	pool, _ := writerPools.LoadOrStore(size, NewWriterPool(size))
+
+	return pool.(monadic.Pool[io.Writer, *bufio.Writer])
+}
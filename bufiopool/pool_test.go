@@ -0,0 +1,92 @@
+package bufiopool_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/bufiopool"
+)
+
+func TestReaderPool(t *testing.T) {
+	t.Parallel()
+
+	pool := bufiopool.NewReaderPool(64)
+
+	f := func(s string) bool {
+		br := pool.Get(strings.NewReader(s))
+		defer pool.Put(br)
+
+		content, err := io.ReadAll(br)
+
+		return err == nil && string(content) == s && br.Size() == 64
+	}
+
+	err := quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestWriterPoolFlushOnPut(t *testing.T) {
+	t.Parallel()
+
+	pool := bufiopool.NewWriterPool(64)
+
+	var b bytes.Buffer
+
+	bw := pool.Get(&b)
+
+	_, _ = bw.WriteString("payload")
+
+	assert.Zero(t, b.Len(), "must be buffered")
+
+	pool.Put(bw)
+
+	assert.Equal(t, "payload", b.String(), "must flush before put")
+	assert.Zero(t, bw.Buffered())
+}
+
+func TestGetPutBySize(t *testing.T) {
+	t.Parallel()
+
+	for _, size := range []int{-1, 0, 8, 16, 512} {
+		size := size
+
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			t.Parallel()
+
+			br := bufiopool.GetReader(strings.NewReader("payload"), size)
+
+			content, err := io.ReadAll(br)
+			require.NoError(t, err)
+			assert.Equal(t, "payload", string(content))
+
+			bufiopool.PutReader(br)
+
+			var b bytes.Buffer
+
+			bw := bufiopool.GetWriter(&b, size)
+			_, _ = bw.WriteString("payload")
+
+			bufiopool.PutWriter(bw)
+
+			assert.Equal(t, "payload", b.String())
+		})
+	}
+}
+
+func ExampleGetWriter() {
+	bw := bufiopool.GetWriter(os.Stdout, 1024)
+	defer bufiopool.PutWriter(bw) // implicit flush
+
+	fmt.Fprintln(bw, "hello, world!")
+
+	// Output:
+	// hello, world!
+}
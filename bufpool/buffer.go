@@ -0,0 +1,36 @@
+package bufpool
+
+import (
+	"bytes"
+
+	"github.com/peczenyj/xpool"
+)
+
+// NewBufferPool returns a pool of [bytes.Buffer], resetted before put back to the pool.
+// Buffers whose capacity exceeds maxCap are dropped instead put back to the pool, see [MaxCapTrimmer].
+// A maxCap less or equal to zero means no limit.
+// The behavior can be customized via [xpool.Option].
+func NewBufferPool(
+	maxCap int,
+	opts ...xpool.Option[*bytes.Buffer],
+) xpool.Pool[*bytes.Buffer] {
+	return newBufferPool(withTrimmer(maxCap, MaxCapTrimmer[*bytes.Buffer], opts))
+}
+
+// NewShrinkingBufferPool returns a pool of [bytes.Buffer], resetted before put back to the pool.
+// Buffers whose capacity exceeds maxCap have their storage released before put back to the pool,
+// instead being dropped, so the [bytes.Buffer] itself is reused, see [ShrinkingTrimmer].
+// A maxCap less or equal to zero means no limit.
+// The behavior can be customized via [xpool.Option].
+func NewShrinkingBufferPool(
+	maxCap int,
+	opts ...xpool.Option[*bytes.Buffer],
+) xpool.Pool[*bytes.Buffer] {
+	return newBufferPool(withTrimmer(maxCap, ShrinkingTrimmer, opts))
+}
+
+func newBufferPool(opts []xpool.Option[*bytes.Buffer]) xpool.Pool[*bytes.Buffer] {
+	return xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, opts...)
+}
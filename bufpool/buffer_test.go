@@ -0,0 +1,45 @@
+package bufpool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool/bufpool"
+)
+
+func TestNewBufferPool(t *testing.T) {
+	t.Parallel()
+
+	pool := bufpool.NewBufferPool(64)
+
+	b := pool.Get()
+	b.WriteString("payload")
+
+	pool.Put(b)
+	assert.Zero(t, b.Len(), "must reset on put")
+
+	large := pool.Get()
+	large.Grow(128)
+
+	pool.Put(large) // must be dropped
+
+	for i := 0; i < 3; i++ {
+		assert.NotSame(t, large, pool.Get())
+	}
+}
+
+func TestNewShrinkingBufferPool(t *testing.T) {
+	t.Parallel()
+
+	pool := bufpool.NewShrinkingBufferPool(64)
+
+	large := pool.Get()
+	large.Grow(128)
+	large.WriteString("payload")
+
+	pool.Put(large)
+
+	assert.Zero(t, large.Len(), "must reset on put")
+	assert.Zero(t, large.Cap(), "must release the storage")
+}
@@ -0,0 +1,90 @@
+package bufpool
+
+import (
+	"unicode/utf8"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Builder is a pooled alternative of [strings.Builder].
+//
+// A [strings.Builder] can't reuse its buffer: String returns a string that shares the
+// underlying bytes, so Reset must drop them. Builder keeps the buffer on Reset, and
+// String returns a copy, so one allocation per string instead one per growth.
+type Builder struct {
+	buf []byte
+}
+
+// Len returns the number of accumulated bytes.
+func (b *Builder) Len() int { return len(b.buf) }
+
+// Cap returns the capacity of the builder's underlying byte slice.
+func (b *Builder) Cap() int { return cap(b.buf) }
+
+// Grow grows b's capacity, if necessary, to guarantee space for another n bytes.
+func (b *Builder) Grow(n int) {
+	if n < 0 {
+		panic("bufpool.Builder.Grow: negative count")
+	}
+
+	if cap(b.buf)-len(b.buf) < n {
+		buf := make([]byte, len(b.buf), 2*cap(b.buf)+n)
+		copy(buf, b.buf)
+		b.buf = buf
+	}
+}
+
+// Write appends the contents of p to b's buffer. It always returns len(p), nil.
+func (b *Builder) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+
+	return len(p), nil
+}
+
+// WriteString appends the contents of s to b's buffer. It always returns len(s), nil.
+func (b *Builder) WriteString(s string) (int, error) {
+	b.buf = append(b.buf, s...)
+
+	return len(s), nil
+}
+
+// WriteByte appends the byte c to b's buffer. It always returns nil.
+func (b *Builder) WriteByte(c byte) error {
+	b.buf = append(b.buf, c)
+
+	return nil
+}
+
+// WriteRune appends the UTF-8 encoding of Unicode code point r to b's buffer.
+// It always returns the length of r and a nil error.
+func (b *Builder) WriteRune(r rune) (int, error) {
+	n := len(b.buf)
+
+	b.buf = utf8.AppendRune(b.buf, r)
+
+	return len(b.buf) - n, nil
+}
+
+// String returns a copy of the accumulated string.
+func (b *Builder) String() string {
+	return string(b.buf)
+}
+
+// Reset resets the [Builder] to be empty, keeping the underlying buffer.
+func (b *Builder) Reset() {
+	b.buf = b.buf[:0]
+}
+
+// NewBuilderPool returns a pool of [Builder], resetted before put back to the pool.
+// Builders whose capacity exceeds maxCap are dropped instead put back to the pool,
+// to avoid that every pooled builder holds the largest string ever built.
+// A maxCap less or equal to zero means no limit.
+// The behavior can be customized via [xpool.Option].
+func NewBuilderPool(
+	maxCap int,
+	opts ...xpool.Option[*Builder],
+) xpool.Pool[*Builder] {
+	return xpool.NewWithResetter(func() *Builder {
+		return new(Builder)
+	}, withTrimmer(maxCap, MaxCapTrimmer[*Builder], opts)...)
+}
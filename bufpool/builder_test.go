@@ -0,0 +1,84 @@
+package bufpool_test
+
+import (
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/bufpool"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Parallel()
+
+	f := func(s string, c byte, r rune) bool {
+		var (
+			b        bufpool.Builder
+			expected strings.Builder
+		)
+
+		b.Grow(len(s))
+		expected.Grow(len(s))
+
+		_, _ = b.WriteString(s)
+		_, _ = b.Write([]byte(s))
+		_ = b.WriteByte(c)
+		_, _ = b.WriteRune(r)
+
+		_, _ = expected.WriteString(s)
+		_, _ = expected.Write([]byte(s))
+		_ = expected.WriteByte(c)
+		_, _ = expected.WriteRune(r)
+
+		return b.String() == expected.String() && b.Len() == expected.Len()
+	}
+
+	err := quick.Check(f, nil)
+	require.NoError(t, err)
+
+	assert.Panics(t, func() {
+		new(bufpool.Builder).Grow(-1)
+	}, "must panic")
+}
+
+func TestBuilderStringIsACopy(t *testing.T) {
+	t.Parallel()
+
+	var b bufpool.Builder
+
+	_, _ = b.WriteString("first")
+
+	s := b.String()
+
+	b.Reset()
+
+	_, _ = b.WriteString("other")
+
+	assert.Equal(t, "first", s)
+	assert.Equal(t, "other", b.String())
+}
+
+func TestNewBuilderPool(t *testing.T) {
+	t.Parallel()
+
+	pool := bufpool.NewBuilderPool(64)
+
+	b := pool.Get()
+	_, _ = b.WriteString("payload")
+
+	pool.Put(b)
+	assert.Zero(t, b.Len(), "must reset on put")
+	assert.NotZero(t, b.Cap(), "must keep the buffer")
+
+	large := pool.Get()
+	large.Grow(128)
+
+	pool.Put(large) // must be dropped
+
+	for i := 0; i < 3; i++ {
+		assert.NotSame(t, large, pool.Get())
+	}
+}
@@ -0,0 +1,73 @@
+package bufpool
+
+import (
+	"bytes"
+
+	"github.com/peczenyj/xpool"
+)
+
+type capper interface {
+	Cap() int
+}
+
+// Grower is implemented by the buffers of this package, like [bytes.Buffer] and [Builder].
+type Grower interface {
+	Cap() int
+	Len() int
+	Grow(n int)
+}
+
+// GetSized fetch one buffer from the pool, like Get, and guarantees that it has space
+// for at least sizeHint bytes, growing it if needed. The caller does not need to check the capacity.
+func GetSized[T Grower](pool xpool.Pool[T], sizeHint int) T {
+	b := pool.Get()
+
+	if sizeHint > b.Cap()-b.Len() {
+		b.Grow(sizeHint)
+	}
+
+	return b
+}
+
+// MaxCapTrimmer returns a [xpool.Trimmer] that drops the buffers whose capacity exceeds maxCap,
+// see [xpool.WithTrimmer].
+func MaxCapTrimmer[T capper](maxCap int) xpool.Trimmer[T] {
+	return xpool.TrimmerFunc[T](func(b T) (T, bool) {
+		return b, b.Cap() <= maxCap
+	})
+}
+
+// ShrinkingTrimmer returns a [xpool.Trimmer] that releases the storage of the buffers whose
+// capacity exceeds maxCap, instead drop them, so the [bytes.Buffer] itself is reused.
+func ShrinkingTrimmer(maxCap int) xpool.Trimmer[*bytes.Buffer] {
+	return xpool.TrimmerFunc[*bytes.Buffer](func(b *bytes.Buffer) (*bytes.Buffer, bool) {
+		if b.Cap() > maxCap {
+			*b = bytes.Buffer{}
+		}
+
+		return b, true
+	})
+}
+
+// withTrimmer prepends the trimmer to the options, if maxCap is positive, so it can be overridden.
+func withTrimmer[T any](maxCap int, trimmer func(int) xpool.Trimmer[T], opts []xpool.Option[T]) []xpool.Option[T] {
+	if maxCap <= 0 {
+		return opts
+	}
+
+	return append([]xpool.Option[T]{xpool.WithTrimmer(trimmer(maxCap))}, opts...)
+}
+
+// WithCapHistogram records the capacity of each buffer put back to the pool, after the trimmer,
+// so the buffers dropped for exceeding maxCap are not observed, to calibrate the maxCap.
+// It is built on top of [xpool.WithOnPutResetCallback], so they can't be combined.
+// Will panic if histogram is nil.
+func WithCapHistogram[T capper](histogram *xpool.Histogram) xpool.Option[T] {
+	if histogram == nil {
+		panic("argument 'histogram' must not be nil")
+	}
+
+	return xpool.WithOnPutResetCallback(func(b T, _ error) {
+		histogram.Observe(float64(b.Cap()))
+	})
+}
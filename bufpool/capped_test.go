@@ -0,0 +1,74 @@
+package bufpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/bufpool"
+)
+
+func TestGetSized(t *testing.T) {
+	t.Parallel()
+
+	buffers := bufpool.NewBufferPool(0)
+
+	b := bufpool.GetSized(buffers, 1000)
+	assert.GreaterOrEqual(t, b.Cap(), 1000)
+	assert.Zero(t, b.Len())
+
+	buffers.Put(b)
+
+	builders := bufpool.NewBuilderPool(0)
+
+	builder := bufpool.GetSized(builders, 10)
+	assert.GreaterOrEqual(t, builder.Cap(), 10)
+
+	builder.WriteString("payload")
+	builders.Put(builder)
+
+	builder = bufpool.GetSized(builders, 100)
+	assert.GreaterOrEqual(t, builder.Cap()-builder.Len(), 100)
+}
+
+func TestMaxCapTrimmer(t *testing.T) {
+	t.Parallel()
+
+	trimmer := bufpool.MaxCapTrimmer[*bufpool.Builder](64)
+
+	small := new(bufpool.Builder)
+
+	_, ok := trimmer.Trim(small)
+	assert.True(t, ok, "must keep small builders")
+
+	large := new(bufpool.Builder)
+	large.Grow(128)
+
+	_, ok = trimmer.Trim(large)
+	assert.False(t, ok, "must drop large builders")
+}
+
+func TestWithCapHistogram(t *testing.T) {
+	t.Parallel()
+
+	histogram := xpool.NewHistogram(64, 1024)
+
+	buffers := bufpool.NewBufferPool(1024, bufpool.WithCapHistogram[*bytes.Buffer](histogram))
+
+	small := new(bytes.Buffer)
+	small.Grow(10)
+
+	large := new(bytes.Buffer)
+	large.Grow(4096)
+
+	buffers.Put(small)
+	buffers.Put(large) // must be dropped
+
+	assert.Equal(t, []uint64{1, 0, 0}, histogram.Snapshot().Counts)
+
+	assert.PanicsWithValue(t, "argument 'histogram' must not be nil", func() {
+		bufpool.WithCapHistogram[*bytes.Buffer](nil)
+	})
+}
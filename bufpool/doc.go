@@ -0,0 +1,10 @@
+// Package bufpool offers ready-made pools of buffers, that drops buffers
+// that grew beyond a configurable capacity instead put them back to the pool.
+//
+//	pool := bufpool.NewBuilderPool(64 << 10)
+//
+//	b := pool.Get()
+//	defer pool.Put(b) // implicit Reset, dropped if the capacity is greater than 64KiB
+//
+// Otherwise, long-running services converge to every pooled buffer holding the largest payload ever seen.
+package bufpool
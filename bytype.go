@@ -0,0 +1,83 @@
+package xpool
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ByType is a heterogeneous pool, it manages one pool per dynamic type, keyed by [reflect.Type],
+// behind a single facade, for plugin-style systems where the set of types is open-ended
+// and the pools can't be enumerated at compile time:
+//
+//	var pools xpool.ByType
+//
+//	event, _ := pools.Get(clickEventType, newClickEvent).(*ClickEvent)
+//	defer pools.Put(event)
+//
+// The objects are not resetted, see [Resetter]. The zero value is ready to use,
+// and a ByType must not be copied after first use.
+type ByType struct {
+	pools sync.Map // reflect.Type -> *sync.Pool
+}
+
+// NewByType returns an empty [ByType].
+func NewByType() *ByType {
+	return new(ByType)
+}
+
+// Get fetch one item from the pool of the type typ. If needed, will call ctor to create another object,
+// that must be of the type typ, or nil. The type is explicit, since the closures created by the same
+// function literal can not be told apart, like the constructors of a plugin system:
+//
+//	func ctorFor(typ reflect.Type) func() any {
+//	  return func() any { return reflect.New(typ.Elem()).Interface() }
+//	}
+//
+// Will panic if typ is nil, or if ctor returns an object of another type.
+func (p *ByType) Get(typ reflect.Type, ctor func() any) any {
+	if typ == nil {
+		panic("argument 'typ' must not be nil")
+	}
+
+	if pool := p.poolOf(typ); pool != nil {
+		if object := pool.Get(); object != nil {
+			return object
+		}
+	}
+
+	object := ctor()
+	if object != nil && reflect.TypeOf(object) != typ {
+		panic(fmt.Sprintf("xpool: the constructor returned %T, expected %s", object, typ))
+	}
+
+	return object
+}
+
+// Put return the object to the pool of its dynamic type. Ignores nil objects.
+func (p *ByType) Put(object any) {
+	if object == nil {
+		return
+	}
+
+	typ := reflect.TypeOf(object)
+
+	pool := p.poolOf(typ)
+	if pool == nil {
+		value, _ := p.pools.LoadOrStore(typ, new(sync.Pool))
+		pool, _ = value.(*sync.Pool)
+	}
+
+	pool.Put(object)
+}
+
+func (p *ByType) poolOf(typ reflect.Type) *sync.Pool {
+	value, ok := p.pools.Load(typ)
+	if !ok {
+		return nil
+	}
+
+	pool, _ := value.(*sync.Pool)
+
+	return pool
+}
@@ -0,0 +1,120 @@
+package xpool_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+var (
+	bufferType  = reflect.TypeOf(new(bytes.Buffer))
+	builderType = reflect.TypeOf(new(strings.Builder))
+)
+
+func newBufferAny() any {
+	return new(bytes.Buffer)
+}
+
+func newBuilderAny() any {
+	return new(strings.Builder)
+}
+
+func TestByType(t *testing.T) {
+	t.Parallel()
+
+	pools := xpool.NewByType()
+
+	buf := pools.Get(bufferType, newBufferAny)
+	require.IsType(t, new(bytes.Buffer), buf)
+
+	builder := pools.Get(builderType, newBuilderAny)
+	require.IsType(t, new(strings.Builder), builder)
+
+	pools.Put(buf)
+	pools.Put(builder)
+	pools.Put(nil)
+
+	got, ok := pools.Get(builderType, newBuilderAny).(*strings.Builder)
+	require.True(t, ok, "must not mix the types")
+
+	requireReused(t, func(b *strings.Builder) {
+		pools.Put(b)
+	}, func() *strings.Builder {
+		b, _ := pools.Get(builderType, newBuilderAny).(*strings.Builder)
+
+		return b
+	}, got)
+}
+
+func TestByTypePutFirst(t *testing.T) {
+	t.Parallel()
+
+	var pools xpool.ByType
+
+	buf := new(bytes.Buffer)
+
+	pools.Put(buf) // the type is not known yet by any constructor
+
+	got, ok := pools.Get(bufferType, newBufferAny).(*bytes.Buffer)
+	require.True(t, ok)
+
+	requireReused(t, func(b *bytes.Buffer) {
+		pools.Put(b)
+	}, func() *bytes.Buffer {
+		b, _ := pools.Get(bufferType, newBufferAny).(*bytes.Buffer)
+
+		return b
+	}, got)
+}
+
+func TestByTypeNilCtor(t *testing.T) {
+	t.Parallel()
+
+	var pools xpool.ByType
+
+	assert.Nil(t, pools.Get(bufferType, func() any { return nil }))
+}
+
+// ctorFor creates closures from the same function literal, like the constructors of a plugin system.
+func ctorFor(typ reflect.Type) func() any {
+	return func() any {
+		return reflect.New(typ.Elem()).Interface()
+	}
+}
+
+func TestByTypeSameLiteral(t *testing.T) {
+	t.Parallel()
+
+	var pools xpool.ByType
+
+	newBuffer, newBuilder := ctorFor(bufferType), ctorFor(builderType)
+
+	pools.Put(pools.Get(bufferType, newBuffer))
+
+	for i := 0; i < 10; i++ {
+		builder := pools.Get(builderType, newBuilder)
+		require.IsType(t, new(strings.Builder), builder, "must not mix the types of closures from the same literal")
+
+		pools.Put(builder)
+	}
+}
+
+func TestByTypeInvalid(t *testing.T) {
+	t.Parallel()
+
+	var pools xpool.ByType
+
+	assert.PanicsWithValue(t, "argument 'typ' must not be nil", func() {
+		pools.Get(nil, newBufferAny)
+	})
+
+	assert.PanicsWithValue(t, "xpool: the constructor returned *strings.Builder, expected *bytes.Buffer", func() {
+		pools.Get(bufferType, newBuilderAny)
+	})
+}
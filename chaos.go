@@ -0,0 +1,29 @@
+package xpool
+
+import "math/rand"
+
+// chaosBackend randomly drops the objects on Put, and randomly misses on Get, see [WithChaos].
+type chaosBackend struct {
+	pool Pool[any]
+	rate float64
+}
+
+func newChaosBackend(pool Pool[any], rate float64) Pool[any] {
+	return &chaosBackend{pool: pool, rate: rate}
+}
+
+func (b *chaosBackend) Get() any {
+	if rand.Float64() < b.rate {
+		return nil // forces a call to the constructor.
+	}
+
+	return b.pool.Get()
+}
+
+func (b *chaosBackend) Put(value any) {
+	if rand.Float64() < b.rate {
+		return // drop it.
+	}
+
+	b.pool.Put(value)
+}
@@ -0,0 +1,52 @@
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithChaos(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithChaos[*bytes.Buffer](1), xpool.WithStats[*bytes.Buffer](&stats))
+
+	for i := 0; i < 10; i++ {
+		pool.Put(pool.Get())
+	}
+
+	assert.Equal(t, uint64(10), stats.Snapshot().News, "must always miss")
+}
+
+func TestWithChaosPartial(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithChaos[*bytes.Buffer](0.5), xpool.WithStats[*bytes.Buffer](&stats))
+
+	for i := 0; i < 1000; i++ {
+		pool.Put(pool.Get())
+	}
+
+	news := stats.Snapshot().News
+	assert.Greater(t, news, uint64(100))
+	assert.Less(t, news, uint64(1000))
+}
+
+func TestWithChaosInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithChaos[*bytes.Buffer](1.5)
+	}, "must panic")
+}
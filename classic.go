@@ -0,0 +1,46 @@
+package xpool
+
+import "sync"
+
+var _ Pool[*struct{}] = (*Classic[*struct{}])(nil)
+
+// Classic is a type-safe pool with the same shape of [sync.Pool]: an optional New field, instead a constructor,
+// and a zero value ready to use. The migration from [sync.Pool] is a type substitution:
+//
+//	var buffers = xpool.Classic[*bytes.Buffer]{
+//	  New: func() *bytes.Buffer { return new(bytes.Buffer) },
+//	}
+//
+// The only call sites to rewrite are the type assertions after Get, like buffers.Get().(*bytes.Buffer),
+// that no longer compile, since Get already returns a T.
+// Like [sync.Pool], a Classic must not be copied after first use, and storing a T that is not pointer-shaped
+// allocates on Put, consider [New] for value types.
+type Classic[T any] struct {
+	// New optionally specifies a function to generate a value when Get would otherwise return the zero value of T.
+	// It may not be changed concurrently with calls to Get.
+	New func() T
+
+	pool sync.Pool
+}
+
+// Get fetch one item from the pool. If the pool is empty, it returns the result of calling New,
+// or the zero value of T if New is nil.
+func (p *Classic[T]) Get() T {
+	// the pool only stores values of type T.
+	if object, ok := p.pool.Get().(T); ok {
+		return object
+	}
+
+	if p.New != nil {
+		return p.New()
+	}
+
+	var zero T
+
+	return zero
+}
+
+// Put adds the object to the pool.
+func (p *Classic[T]) Put(object T) {
+	p.pool.Put(object)
+}
@@ -0,0 +1,52 @@
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestClassic(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.Classic[*bytes.Buffer]{
+		New: func() *bytes.Buffer {
+			return new(bytes.Buffer)
+		},
+	}
+
+	buf := pool.Get()
+	require.NotNil(t, buf)
+
+	put := func(b *bytes.Buffer) {
+		b.Reset()
+		b.WriteString("foo")
+		pool.Put(b)
+	}
+
+	buf = requireReused(t, put, pool.Get, buf)
+
+	assert.Equal(t, "foo", buf.String(), "must not be resetted, like sync.Pool")
+}
+
+func TestClassicZeroValue(t *testing.T) {
+	t.Parallel()
+
+	var pool xpool.Classic[*bytes.Buffer]
+
+	assert.Nil(t, pool.Get(), "must return the zero value without New")
+
+	var ints xpool.Classic[int]
+
+	assert.Zero(t, ints.Get())
+
+	ints.Put(42)
+
+	if got := ints.Get(); got != 0 {
+		assert.Equal(t, 42, got)
+	}
+}
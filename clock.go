@@ -0,0 +1,43 @@
+package xpool
+
+import "time"
+
+// Clock abstracts the time for the time-based features, like the idle eviction of the ring pool,
+// so they can be tested with a fake clock, like the one of the package xpooltest.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a new [Timer] that will send the current time on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the subset of [time.Timer] used by a [Clock].
+type Timer interface {
+	// C returns the channel where the time is delivered.
+	C() <-chan time.Time
+	// Stop prevents the timer from firing, see [time.Timer.Stop].
+	Stop() bool
+	// Reset changes the timer to expire after duration d, see [time.Timer.Reset].
+	Reset(d time.Duration) bool
+}
+
+// SystemClock is the [Clock] backed by the package [time].
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
@@ -0,0 +1,25 @@
+package xpool_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestSystemClock(t *testing.T) {
+	t.Parallel()
+
+	before := time.Now()
+
+	assert.False(t, xpool.SystemClock.Now().Before(before))
+
+	timer := xpool.SystemClock.NewTimer(time.Millisecond)
+
+	fired := <-timer.C()
+	assert.False(t, fired.Before(before))
+
+	assert.False(t, timer.Stop(), "must not be active after firing")
+}
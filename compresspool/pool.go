@@ -0,0 +1,136 @@
+// Package compresspool offers ready-made monadic pools of compressors and decompressors,
+// handling their error-returning Reset signatures.
+//
+// The state of each pool is the underlying [io.Writer] or [io.Reader]:
+//
+//	pool, err := compresspool.NewGzipWriterPool(gzip.BestSpeed)
+//	...
+//	zw := pool.Get(w)  // implicit Reset(w)
+//	defer pool.Put(zw) // implicit Reset(io.Discard)
+//
+//	... // write the payload
+//
+//	err = zw.Close() // it is still necessary to flush the compressed stream
+//
+// Encoders and decoders from other libraries, like zstd, can be pooled via [NewEncoderPool] and [NewDecoderPool].
+package compresspool
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+
+	"github.com/peczenyj/xpool/monadic"
+)
+
+// Encoder is the interface implemented by streaming compressors, like [gzip.Writer] and [flate.Writer].
+type Encoder interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// Decoder is the interface implemented by streaming decompressors, like [gzip.Reader].
+type Decoder interface {
+	io.Reader
+	Reset(r io.Reader) error
+}
+
+// NewEncoderPool returns a monadic pool of [Encoder].
+// Before put back to the pool, the encoder is resetted to write into [io.Discard],
+// and it is closed when discarded by the pool.
+// The behavior can be customized via [monadic.Option].
+func NewEncoderPool[T Encoder](
+	ctor func() T,
+	opts ...monadic.Option[io.Writer, T],
+) monadic.Pool[io.Writer, T] {
+	return monadic.NewWithResetCloser(ctor,
+		append([]monadic.Option[io.Writer, T]{monadic.WithPutState[io.Writer, T](io.Discard)}, opts...)...,
+	)
+}
+
+// NewDecoderPool returns a monadic pool of [Decoder].
+// If Reset fails on Get there is no retry, since the reader was consumed, see [monadic.WithNoRetryOnGet].
+// Before put back to the pool, the decoder is resetted to read from an empty reader.
+// The behavior can be customized via [monadic.Option].
+func NewDecoderPool[T Decoder](
+	ctor func() T,
+	opts ...monadic.Option[io.Reader, T],
+) monadic.Pool[io.Reader, T] {
+	resetToEOF := monadic.WithPutResetter[io.Reader](func(object T) {
+		_ = object.Reset(eofReader{}) // decoders may report io.EOF while reading the header.
+	})
+
+	return monadic.NewE(ctor, append([]monadic.Option[io.Reader, T]{
+		resetToEOF,
+		monadic.WithNoRetryOnGet[io.Reader, T](),
+	}, opts...)...)
+}
+
+// NewGzipWriterPool returns a monadic pool of [gzip.Writer] with the given compression level.
+// Returns an error if the level is invalid.
+func NewGzipWriterPool(
+	level int,
+	opts ...monadic.Option[io.Writer, *gzip.Writer],
+) (monadic.Pool[io.Writer, *gzip.Writer], error) {
+	if _, err := gzip.NewWriterLevel(nil, level); err != nil {
+		return nil, err
+	}
+
+	return NewEncoderPool(func() *gzip.Writer {
+		zw, _ := gzip.NewWriterLevel(nil, level)
+
+		return zw
+	}, opts...), nil
+}
+
+// NewGzipReaderPool returns a monadic pool of [gzip.Reader].
+// The gzip header is read on Get, use GetContext to check for errors.
+func NewGzipReaderPool(
+	opts ...monadic.Option[io.Reader, *gzip.Reader],
+) monadic.Pool[io.Reader, *gzip.Reader] {
+	return NewDecoderPool(func() *gzip.Reader {
+		return new(gzip.Reader)
+	}, opts...)
+}
+
+// NewFlateWriterPool returns a monadic pool of [flate.Writer] with the given compression level.
+// Returns an error if the level is invalid.
+func NewFlateWriterPool(
+	level int,
+	opts ...monadic.Option[io.Writer, *flate.Writer],
+) (monadic.Pool[io.Writer, *flate.Writer], error) {
+	if _, err := flate.NewWriter(nil, level); err != nil {
+		return nil, err
+	}
+
+	return NewEncoderPool(func() *flate.Writer {
+		zw, _ := flate.NewWriter(nil, level)
+
+		return zw
+	}, opts...), nil
+}
+
+// NewFlateReaderPool returns a monadic pool of flate readers, that implements [flate.Resetter].
+// To use a preset dictionary, see the package https://github.com/peczenyj/xpool/dyadic.
+func NewFlateReaderPool(
+	opts ...monadic.Option[io.Reader, io.ReadCloser],
+) monadic.Pool[io.Reader, io.ReadCloser] {
+	resetToEOF := monadic.WithPutState[io.Reader, io.ReadCloser](eofReader{})
+
+	return monadic.NewWithCustomResetterE(func() io.ReadCloser {
+		return flate.NewReader(eofReader{})
+	}, func(object io.ReadCloser, state io.Reader) error {
+		return object.(flate.Resetter).Reset(state, nil)
+	}, append([]monadic.Option[io.Reader, io.ReadCloser]{
+		resetToEOF,
+		monadic.WithNoRetryOnGet[io.Reader, io.ReadCloser](),
+	}, opts...)...)
+}
+
+// eofReader is a stateless empty reader, that also implements [io.ByteReader]
+// to avoid the allocation of a bufio.Reader by the decompressors.
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
+
+func (eofReader) ReadByte() (byte, error) { return 0, io.EOF }
@@ -0,0 +1,103 @@
+package compresspool_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/compresspool"
+)
+
+func TestGzipRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	writers, err := compresspool.NewGzipWriterPool(gzip.BestSpeed)
+	require.NoError(t, err)
+
+	readers := compresspool.NewGzipReaderPool()
+
+	f := func(payload []byte) bool {
+		var b bytes.Buffer
+
+		zw := writers.Get(&b)
+		defer writers.Put(zw)
+
+		_, _ = zw.Write(payload)
+
+		if err := zw.Close(); err != nil {
+			return false
+		}
+
+		zr, err := readers.GetContext(context.Background(), &b)
+		if err != nil {
+			return false
+		}
+		defer readers.Put(zr)
+
+		content, err := io.ReadAll(zr)
+
+		return err == nil && bytes.Equal(payload, content)
+	}
+
+	err = quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestFlateRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	writers, err := compresspool.NewFlateWriterPool(-1)
+	require.NoError(t, err)
+
+	readers := compresspool.NewFlateReaderPool()
+
+	f := func(payload []byte) bool {
+		var b bytes.Buffer
+
+		zw := writers.Get(&b)
+		defer writers.Put(zw)
+
+		_, _ = zw.Write(payload)
+
+		if err := zw.Close(); err != nil {
+			return false
+		}
+
+		zr := readers.Get(&b)
+		defer readers.Put(zr)
+
+		content, err := io.ReadAll(zr)
+
+		return err == nil && bytes.Equal(payload, content)
+	}
+
+	err = quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestInvalidLevel(t *testing.T) {
+	t.Parallel()
+
+	_, err := compresspool.NewGzipWriterPool(42)
+	require.Error(t, err)
+
+	_, err = compresspool.NewFlateWriterPool(42)
+	require.Error(t, err)
+}
+
+func TestGzipInvalidHeader(t *testing.T) {
+	t.Parallel()
+
+	readers := compresspool.NewGzipReaderPool()
+
+	zr, err := readers.GetContext(context.Background(), strings.NewReader("not a gzip stream"))
+	require.ErrorIs(t, err, gzip.ErrHeader)
+	assert.Nil(t, zr)
+}
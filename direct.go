@@ -0,0 +1,75 @@
+package xpool
+
+import "sync"
+
+var _ Pool[*struct{}] = (*DirectPool[*struct{}])(nil)
+
+// DirectPool is a concrete pool of objects of type T, for the hot loops.
+// Different than the [Pool] interface returned by [New], the calls to Get and Put on a *DirectPool
+// are direct, so the compiler can inline them. It does not accept any [Option],
+// use [New] and its variants when the pool needs more than a constructor and a resetter.
+type DirectPool[T any] struct {
+	pool     sync.Pool
+	ctor     func() T
+	resetter func(object T)
+}
+
+// NewDirect returns a [DirectPool] for a given generic type T, like [New].
+// T must be pointer-shaped, like a pointer or a map, so Put does not allocate to store it.
+// Will panic if ctor is nil or if T is not pointer-shaped, like a struct, an array, a string or a slice.
+func NewDirect[T any](ctor func() T) *DirectPool[T] {
+	return NewDirectWithCustomResetter(ctor, nil)
+}
+
+// NewDirectWithResetter returns a [DirectPool] that calls Reset() before put the object back to the pool,
+// like [NewWithResetter].
+func NewDirectWithResetter[T Resetter](ctor func() T) *DirectPool[T] {
+	return NewDirectWithCustomResetter(ctor, func(object T) {
+		object.Reset()
+	})
+}
+
+// NewDirectWithCustomResetter returns a [DirectPool] that calls the resetter, if not nil,
+// before put the object back to the pool, like [NewWithCustomResetter].
+// Be careful, the resetter must be thread safe.
+func NewDirectWithCustomResetter[T any](ctor func() T, resetter func(object T)) *DirectPool[T] {
+	if ctor == nil {
+		panic("argument 'ctor' must not be nil")
+	}
+
+	if needsBoxing[T]() {
+		panic("type parameter 'T' must be pointer-shaped")
+	}
+
+	return &DirectPool[T]{
+		ctor:     ctor,
+		resetter: resetter,
+	}
+}
+
+// Get fetch one item from object pool. If needed, will create another object.
+func (p *DirectPool[T]) Get() T {
+	// the pool only stores values of type T.
+	if object, ok := p.pool.Get().(T); ok {
+		return object
+	}
+
+	return p.ctor()
+}
+
+// Put return the object to the pool, calling the resetter first, if any.
+func (p *DirectPool[T]) Put(object T) {
+	if p.resetter != nil {
+		p.resetter(object)
+	}
+
+	p.pool.Put(object)
+}
+
+func (p *DirectPool[T]) resetOnPut(object T) error {
+	if p.resetter != nil {
+		p.resetter(object)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestNewDirect(t *testing.T) {
+	t.Parallel()
+
+	var pool xpool.Pool[*bytes.Buffer] = xpool.NewDirect(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	buf := pool.Get()
+	require.NotNil(t, buf)
+
+	buf.WriteString("foo")
+
+	pool.Put(buf)
+
+	assert.Equal(t, "foo", buf.String(), "must not be resetted")
+}
+
+func TestNewDirectWithResetter(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewDirectWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	buf := pool.Get()
+	buf.WriteString("foo")
+
+	pool.Put(buf)
+
+	assert.Zero(t, buf.Len(), "must be resetted on put")
+
+	requireReused(t, pool.Put, pool.Get, pool.Get())
+}
+
+func TestNewDirectInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'ctor' must not be nil", func() {
+		xpool.NewDirect[*bytes.Buffer](nil)
+	})
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be pointer-shaped", func() {
+		xpool.NewDirect(func() bytes.Buffer { return bytes.Buffer{} })
+	})
+}
+
+func TestDirectPoolNoAllocations(t *testing.T) {
+	pool := xpool.NewDirectWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	pool.Put(pool.Get()) // warm up the pool
+
+	allocs := testing.AllocsPerRun(100, func() {
+		pool.Put(pool.Get())
+	})
+
+	assert.Zero(t, allocs, "a warm pool must not allocate")
+}
+
+func BenchmarkDirectPool(b *testing.B) {
+	pool := xpool.NewDirectWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		pool.Put(pool.Get())
+	}
+}
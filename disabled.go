@@ -0,0 +1,61 @@
+package xpool
+
+import (
+	"context"
+	"os"
+	"strconv"
+)
+
+// DisableEnv is the environment variable that disables the pooling of all pools, see [WithDisabled].
+// It is read when the pool is created, and accepts the values of [strconv.ParseBool], like "1" or "true".
+const DisableEnv = "XPOOL_DISABLE"
+
+func disabledByEnv() bool {
+	disabled, _ := strconv.ParseBool(os.Getenv(DisableEnv))
+
+	return disabled
+}
+
+// disabledPool creates a new object on each Get, and drops the objects on Put, see [WithDisabled].
+type disabledPool[T any] struct {
+	ctor      func() T
+	onDiscard func(T)
+	stats     *Stats
+}
+
+func newDisabledPool[T any](ctor func() T, o *options[T]) *disabledPool[T] {
+	return &disabledPool[T]{
+		ctor:      ctor,
+		onDiscard: o.onDiscard,
+		stats:     o.stats,
+	}
+}
+
+func (p *disabledPool[T]) Get() T {
+	p.stats.incGets()
+	p.stats.incNews()
+
+	return p.ctor()
+}
+
+func (p *disabledPool[T]) GetContext(ctx context.Context) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return p.Get(), nil
+}
+
+func (p *disabledPool[T]) Put(object T) {
+	p.stats.incPuts()
+
+	p.Discard(object)
+}
+
+func (p *disabledPool[T]) Discard(object T) {
+	if p.onDiscard != nil {
+		p.onDiscard(object)
+	}
+}
@@ -0,0 +1,50 @@
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithDisabled(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stats     xpool.Stats
+		discarded []*bytes.Buffer
+	)
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithDisabled[*bytes.Buffer](true), xpool.WithStats[*bytes.Buffer](&stats),
+		xpool.WithOnDiscard(func(b *bytes.Buffer) {
+			discarded = append(discarded, b)
+		}))
+
+	buffer := pool.Get()
+	buffer.WriteString("state")
+	pool.Put(buffer)
+
+	other := pool.Get()
+	assert.NotSame(t, buffer, other, "must always create")
+	assert.Equal(t, "state", buffer.String(), "must not reset")
+	assert.Len(t, discarded, 1)
+
+	assert.Equal(t, xpool.StatsSnapshot{Gets: 2, Puts: 1, News: 2}, stats.Snapshot())
+}
+
+func TestDisableEnv(t *testing.T) {
+	t.Setenv(xpool.DisableEnv, "1")
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	buffer := pool.Get()
+	pool.Put(buffer)
+
+	assert.NotSame(t, buffer, pool.Get(), "must always create")
+}
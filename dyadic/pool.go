@@ -0,0 +1,103 @@
+// The intent of dyadic is to support dyadic objects, where the Reset method expects two arguments.
+//
+// Different than [monadic.Pool], the dyadic [Pool] handle three different generic types: S1, S2 and T
+//   - T is the type of the object returned from the pool
+//   - S1 and S2 are the state, where we set before return an object, and reset it back to zero values when put back to the pool.
+//
+// For instance, the [flate.Resetter] expects an [io.Reader] and a dictionary:
+//
+//	pool := dyadic.NewWithCustomResetterE(func() io.ReadCloser {
+//	  return flate.NewReader(nil)
+//	}, func(object io.ReadCloser, r io.Reader, dict []byte) error {
+//	  return object.(flate.Resetter).Reset(r, dict)
+//	})
+//
+//	zr := pool.Get(reader, dict) // implicit Reset(reader, dict)
+//	defer pool.Put(zr)           // implicit Reset(nil, nil)
+//
+// Instead wrap the arguments in a struct to use a [monadic.Pool].
+package dyadic
+
+import (
+	"github.com/peczenyj/xpool/monadic"
+)
+
+// Pool dyadic is a type-safe object pool interface.
+// This interface is parameterized on three generic types:
+//   - T is reserved for the type of the object that will be stored on the pool.
+//   - S1 and S2 are reserved for the status of the object to be setted before return the object from the pool.
+type Pool[S1, S2, T any] interface {
+	// Get fetch one item from object pool. If needed, will create another object.
+	// The states S1 and S2 will be used in the resetter.
+	Get(a S1, b S2) T
+
+	// Put return the object to the pull.
+	// The zero values of S1 and S2 will be used in the resetter.
+	Put(object T)
+}
+
+// Resetter dyadic interface.
+type Resetter[S1, S2 any] interface {
+	Reset(a S1, b S2)
+}
+
+// New is the constructor of an [Pool] for a given set of generic types S1, S2 and T.
+// Receives the constructor of the type T.
+// It sets a trivial resetter, T must be a [Resetter]
+// will call Reset(a S1, b S2) before return the object on Get(a S1, b S2)
+// will call Reset(zero values of S1 and S2) before push back to the pool.
+func New[S1, S2 any, T Resetter[S1, S2]](
+	ctor func() T,
+) Pool[S1, S2, T] {
+	return NewWithCustomResetter(ctor, func(object T, a S1, b S2) {
+		object.Reset(a, b)
+	})
+}
+
+// NewWithCustomResetter is the constructor of an [Pool] for a given set of generic types S1, S2 and T.
+// Receives the constructor of the type T as a callback.
+// We can specify a special resetter, to be called with zero values of S1 and S2 before
+// return the object from the pool.
+// Be careful, the custom resetter must be thread safe.
+func NewWithCustomResetter[S1, S2, T any](
+	ctor func() T,
+	customResetter func(object T, a S1, b S2),
+) Pool[S1, S2, T] {
+	return &dyadicPool[S1, S2, T]{
+		pool: monadic.NewWithCustomResetter(ctor, func(object T, s states[S1, S2]) {
+			customResetter(object, s.a, s.b)
+		}),
+	}
+}
+
+// NewWithCustomResetterE is the constructor of an [Pool] for a given set of generic types S1, S2 and T.
+// Similar to [NewWithCustomResetter], but the custom resetter may return an error,
+// with the same discard semantics of [monadic.NewWithCustomResetterE].
+// Be careful, the custom resetter must be thread safe.
+func NewWithCustomResetterE[S1, S2, T any](
+	ctor func() T,
+	customResetter func(object T, a S1, b S2) error,
+) Pool[S1, S2, T] {
+	return &dyadicPool[S1, S2, T]{
+		pool: monadic.NewWithCustomResetterE(ctor, func(object T, s states[S1, S2]) error {
+			return customResetter(object, s.a, s.b)
+		}),
+	}
+}
+
+type states[S1, S2 any] struct {
+	a S1
+	b S2
+}
+
+type dyadicPool[S1, S2, T any] struct {
+	pool monadic.Pool[states[S1, S2], T]
+}
+
+func (p *dyadicPool[S1, S2, T]) Get(a S1, b S2) T {
+	return p.pool.Get(states[S1, S2]{a: a, b: b})
+}
+
+func (p *dyadicPool[_, _, T]) Put(object T) {
+	p.pool.Put(object)
+}
@@ -0,0 +1,103 @@
+package dyadic_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"os"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/dyadic"
+)
+
+type pair struct {
+	prefix string
+	suffix string
+}
+
+func (p *pair) Reset(prefix, suffix string) {
+	p.prefix, p.suffix = prefix, suffix
+}
+
+func TestResetterDyadic(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		label string
+		pool  dyadic.Pool[string, string, *pair]
+	}{
+		{
+			label: "dyadic New + implicit default Reset",
+			pool:  dyadic.New[string, string](func() *pair { return new(pair) }),
+		},
+		{
+			label: "dyadic NewWithCustomResetter + explicit custom Reset",
+			pool: dyadic.NewWithCustomResetter(func() *pair {
+				return new(pair)
+			}, func(p *pair, prefix, suffix string) {
+				p.Reset(prefix, suffix)
+			}),
+		},
+		{
+			label: "dyadic NewWithCustomResetterE + explicit custom Reset",
+			pool: dyadic.NewWithCustomResetterE(func() *pair {
+				return new(pair)
+			}, func(p *pair, prefix, suffix string) error {
+				p.Reset(prefix, suffix)
+
+				return nil
+			}),
+		},
+	}
+
+	for _, testCase := range testCases {
+		pool := testCase.pool
+
+		t.Run(testCase.label, func(t *testing.T) {
+			t.Parallel()
+
+			f := func(prefix, suffix string) bool {
+				p := pool.Get(prefix, suffix)
+				defer func() {
+					pool.Put(p)
+
+					if p.prefix != "" || p.suffix != "" {
+						t.Errorf("object must be resetted to zero values on put")
+					}
+				}()
+
+				return p.prefix == prefix && p.suffix == suffix
+			}
+
+			err := quick.Check(f, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func ExampleNewWithCustomResetterE() {
+	dict := []byte(`hello, world!`)
+
+	pool := dyadic.NewWithCustomResetterE(func() io.ReadCloser {
+		return flate.NewReader(nil)
+	}, func(object io.ReadCloser, r io.Reader, dict []byte) error {
+		return object.(flate.Resetter).Reset(r, dict)
+	})
+
+	var b bytes.Buffer
+
+	zw, _ := flate.NewWriterDict(&b, flate.BestCompression, dict)
+	_, _ = zw.Write([]byte("hello, world!\n"))
+	_ = zw.Close()
+
+	zr := pool.Get(&b, dict)
+	defer pool.Put(zr)
+
+	_, _ = io.Copy(os.Stdout, zr)
+
+	// Output:
+	// hello, world!
+}
@@ -0,0 +1,44 @@
+//go:build go1.24
+
+package xpool
+
+import "runtime"
+
+// evictionBackend counts the objects reclaimed by the garbage collection while stored on the pool,
+// see [WithEvictionStats]. Each stored object is wrapped with a cleanup, stopped when it is fetched.
+type evictionBackend struct {
+	pool  Pool[any]
+	stats *Stats
+}
+
+type evictable struct {
+	value   any
+	cleanup runtime.Cleanup
+}
+
+func newEvictionBackend(pool Pool[any], stats *Stats) Pool[any] {
+	if stats == nil {
+		return pool
+	}
+
+	return &evictionBackend{pool: pool, stats: stats}
+}
+
+func (b *evictionBackend) Get() any {
+	value := b.pool.Get()
+
+	if e, ok := value.(*evictable); ok {
+		e.cleanup.Stop()
+
+		return e.value
+	}
+
+	return value
+}
+
+func (b *evictionBackend) Put(value any) {
+	e := &evictable{value: value}
+	e.cleanup = runtime.AddCleanup(e, (*Stats).incEvictions, b.stats)
+
+	b.pool.Put(e)
+}
@@ -0,0 +1,8 @@
+//go:build !go1.24
+
+package xpool
+
+// newEvictionBackend is a no-op before Go 1.24, since it requires runtime.AddCleanup.
+func newEvictionBackend(pool Pool[any], _ *Stats) Pool[any] {
+	return pool
+}
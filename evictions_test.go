@@ -0,0 +1,40 @@
+//go:build go1.24
+
+package xpool_test
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithEvictionStats(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStats[*bytes.Buffer](&stats), xpool.WithEvictionStats[*bytes.Buffer]())
+
+	reused := pool.Get()
+	pool.Put(reused)
+	pool.Put(pool.Get()) // the cleanup of a reused object must be stopped
+
+	for i := 0; i < 10; i++ {
+		pool.Put(new(bytes.Buffer))
+	}
+
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+
+		return stats.Snapshot().Evictions > 0
+	}, time.Second, 10*time.Millisecond, "must count the objects reclaimed by the GC")
+
+	assert.LessOrEqual(t, stats.Snapshot().Evictions, uint64(12), "at most one eviction per Put")
+}
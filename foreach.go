@@ -0,0 +1,117 @@
+package xpool
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ForEach fetch one object from the pool and calls fn for each item, reusing the same object,
+// stopping on the first error, that is returned. The object is put back to the pool at the end,
+// even if fn panics. Useful on batch jobs, where Get and Put per item are pure overhead.
+// Will panic if pool or fn are nil.
+func ForEach[T, I any](pool Pool[T], items []I, fn func(object T, item I) error) error {
+	if pool == nil {
+		panic("argument 'pool' must not be nil")
+	}
+
+	if fn == nil {
+		panic("callback 'fn' must not be nil")
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	object := pool.Get()
+	defer pool.Put(object)
+
+	for _, item := range items {
+		if err := fn(object, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ForEachParallel is like [ForEach], but the items are processed by up to workers goroutines,
+// each one with its own object fetched from the pool, in no specific order.
+// After the first error, the remaining items are skipped and the first error is returned.
+// If fn panics, the worker puts its object back to the pool, the remaining items are skipped,
+// and the panic is propagated to the caller, after all workers put their objects back to the pool.
+// Be careful, fn must be thread safe.
+// Will panic if pool or fn are nil, or if workers is not positive.
+func ForEachParallel[T, I any](pool Pool[T], items []I, workers int, fn func(object T, item I) error) error {
+	if pool == nil {
+		panic("argument 'pool' must not be nil")
+	}
+
+	if fn == nil {
+		panic("callback 'fn' must not be nil")
+	}
+
+	if workers <= 0 {
+		panic("argument 'workers' must be positive")
+	}
+
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	var (
+		next     int64 = -1
+		failed   uint32
+		firstErr error
+		panicked any
+		once     sync.Once
+		wg       sync.WaitGroup
+	)
+
+	stop := func(err error, recovered any) {
+		once.Do(func() {
+			firstErr, panicked = err, recovered
+			atomic.StoreUint32(&failed, 1)
+		})
+	}
+
+	worker := func() {
+		defer wg.Done()
+
+		object := pool.Get()
+		defer pool.Put(object)
+
+		// a panic on a worker goroutine would crash the process, so it is propagated to the caller.
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				stop(nil, recovered)
+			}
+		}()
+
+		for atomic.LoadUint32(&failed) == 0 {
+			i := atomic.AddInt64(&next, 1)
+			if i >= int64(len(items)) {
+				return
+			}
+
+			if err := fn(object, items[i]); err != nil {
+				stop(err, nil)
+
+				return
+			}
+		}
+	}
+
+	wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go worker()
+	}
+
+	wg.Wait()
+
+	if panicked != nil {
+		panic(panicked)
+	}
+
+	return firstErr
+}
@@ -0,0 +1,200 @@
+package xpool_test
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestForEach(t *testing.T) {
+	t.Parallel()
+
+	var outstanding xpool.Outstanding
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOutstanding[*bytes.Buffer](&outstanding))
+
+	var (
+		used []*bytes.Buffer
+		out  []string
+	)
+
+	err := xpool.ForEach(pool, []int{1, 2, 3}, func(buf *bytes.Buffer, item int) error {
+		used = append(used, buf)
+
+		buf.Reset()
+		buf.WriteString(strconv.Itoa(item * 10))
+		out = append(out, buf.String())
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"10", "20", "30"}, out)
+	require.Len(t, used, 3)
+	assert.Same(t, used[0], used[1], "must reuse the same object")
+	assert.Same(t, used[0], used[2], "must reuse the same object")
+	assert.Equal(t, 0, outstanding.InFlight())
+}
+
+func TestForEachError(t *testing.T) {
+	t.Parallel()
+
+	var outstanding xpool.Outstanding
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOutstanding[*bytes.Buffer](&outstanding))
+
+	errBoom := errors.New("boom")
+
+	var calls int
+
+	err := xpool.ForEach(pool, []int{1, 2, 3}, func(_ *bytes.Buffer, item int) error {
+		calls++
+
+		if item == 2 {
+			return errBoom
+		}
+
+		return nil
+	})
+	require.ErrorIs(t, err, errBoom)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 0, outstanding.InFlight())
+
+	assert.Panics(t, func() {
+		_ = xpool.ForEach(pool, []int{1}, func(*bytes.Buffer, int) error {
+			panic("boom")
+		})
+	})
+	assert.Equal(t, 0, outstanding.InFlight(), "must put the object back even on panic")
+
+	require.NoError(t, xpool.ForEach(pool, nil, func(*bytes.Buffer, int) error {
+		return errBoom
+	}))
+}
+
+func TestForEachParallel(t *testing.T) {
+	t.Parallel()
+
+	var outstanding xpool.Outstanding
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOutstanding[*bytes.Buffer](&outstanding))
+
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i
+	}
+
+	var (
+		mu      sync.Mutex
+		sum     int
+		objects = make(map[*bytes.Buffer]struct{})
+	)
+
+	err := xpool.ForEachParallel(pool, items, 4, func(buf *bytes.Buffer, item int) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		sum += item
+		objects[buf] = struct{}{}
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4950, sum)
+	assert.LessOrEqual(t, len(objects), 4, "one object per worker")
+	assert.Equal(t, 0, outstanding.InFlight())
+
+	require.NoError(t, xpool.ForEachParallel(pool, items[:0], 4, func(*bytes.Buffer, int) error {
+		return errors.New("must not be called")
+	}))
+}
+
+func TestForEachParallelError(t *testing.T) {
+	t.Parallel()
+
+	var outstanding xpool.Outstanding
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOutstanding[*bytes.Buffer](&outstanding))
+
+	errBoom := errors.New("boom")
+
+	var calls int64
+
+	err := xpool.ForEachParallel(pool, make([]int, 1000), 2, func(*bytes.Buffer, int) error {
+		atomic.AddInt64(&calls, 1)
+
+		return errBoom
+	})
+	require.ErrorIs(t, err, errBoom)
+	assert.LessOrEqual(t, atomic.LoadInt64(&calls), int64(2), "must skip the remaining items")
+	assert.Equal(t, 0, outstanding.InFlight())
+}
+
+func TestForEachParallelPanic(t *testing.T) {
+	t.Parallel()
+
+	var outstanding xpool.Outstanding
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOutstanding[*bytes.Buffer](&outstanding))
+
+	var calls int64
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = xpool.ForEachParallel(pool, make([]int, 1000), 2, func(*bytes.Buffer, int) error {
+			atomic.AddInt64(&calls, 1)
+
+			panic("boom")
+		})
+	}, "must propagate the panic to the caller")
+	assert.LessOrEqual(t, atomic.LoadInt64(&calls), int64(2), "must skip the remaining items")
+	assert.Equal(t, 0, outstanding.InFlight(), "must put the objects back even on panic")
+}
+
+func TestForEachInvalid(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	fn := func(*bytes.Buffer, int) error { return nil }
+
+	assert.PanicsWithValue(t, "argument 'pool' must not be nil", func() {
+		_ = xpool.ForEach[*bytes.Buffer, int](nil, nil, fn)
+	})
+
+	assert.PanicsWithValue(t, "callback 'fn' must not be nil", func() {
+		_ = xpool.ForEach[*bytes.Buffer, int](pool, nil, nil)
+	})
+
+	assert.PanicsWithValue(t, "argument 'pool' must not be nil", func() {
+		_ = xpool.ForEachParallel[*bytes.Buffer, int](nil, nil, 1, fn)
+	})
+
+	assert.PanicsWithValue(t, "callback 'fn' must not be nil", func() {
+		_ = xpool.ForEachParallel[*bytes.Buffer, int](pool, nil, 1, nil)
+	})
+
+	assert.PanicsWithValue(t, "argument 'workers' must be positive", func() {
+		_ = xpool.ForEachParallel(pool, []int{1}, 0, fn)
+	})
+}
@@ -0,0 +1,136 @@
+// Package freelist offers an intrusive free list of pointers: the link to the next idle object
+// is stored inside the object itself, via an embedded [Hook], so Get and Put do not allocate.
+//
+//	type Node struct {
+//	  freelist.Hook[*Node]
+//	  Edges []*Node
+//	}
+//
+//	nodes := freelist.New(func() *Node {
+//	  return new(Node)
+//	})
+//
+//	node := nodes.Get()
+//	defer nodes.Put(node)
+//
+// The idle objects are reused in LIFO order, the most recently put object is the first returned,
+// and they survive the garbage collection. The list is unbounded.
+package freelist
+
+import "sync"
+
+// Hook must be embedded in the object to be stored on a [List].
+// The zero value is ready to use, and it must not be modified by the user.
+type Hook[P any] struct {
+	next P
+}
+
+// FreeListHook returns the hook, it is used by the [List] to link the idle objects.
+func (h *Hook[P]) FreeListHook() *Hook[P] {
+	return h
+}
+
+// Node is the constraint of the objects stored on a [List], usually a pointer to a struct that embeds [Hook].
+type Node[P any] interface {
+	comparable
+	FreeListHook() *Hook[P]
+}
+
+// List is an intrusive free list, safe for concurrent use.
+type List[P Node[P]] struct {
+	mu          sync.Mutex
+	head        P
+	tail        P
+	len         int
+	ctor        func() P
+	onDuplicate func(object P)
+}
+
+// New returns an empty [List], receives the constructor of the type P.
+// The behavior can be customized via [Option].
+func New[P Node[P]](ctor func() P, opts ...Option[P]) *List[P] {
+	o := buildOptions(opts)
+
+	return &List[P]{ctor: ctor, onDuplicate: o.onDuplicate}
+}
+
+// Get fetch the most recently put object from the list. If the list is empty, will create another object.
+func (l *List[P]) Get() P {
+	var zero P
+
+	l.mu.Lock()
+
+	object := l.head
+	if object == zero {
+		l.mu.Unlock()
+
+		return l.ctor()
+	}
+
+	hook := object.FreeListHook()
+	l.head, hook.next = hook.next, zero
+	l.len--
+
+	if l.head == zero {
+		l.tail = zero
+	}
+
+	l.mu.Unlock()
+
+	return object
+}
+
+// Put return the object to the list.
+// Be careful, the object must not be put back twice, see [WithDuplicateCheck].
+func (l *List[P]) Put(object P) {
+	var zero P
+
+	if object == zero {
+		return
+	}
+
+	l.mu.Lock()
+
+	hook := object.FreeListHook()
+
+	// an idle object is linked to the next one, unless it is the tail.
+	if l.onDuplicate != nil && (hook.next != zero || object == l.tail) {
+		l.mu.Unlock()
+
+		l.onDuplicate(object)
+
+		return
+	}
+
+	if l.head == zero {
+		l.tail = object
+	}
+
+	hook.next = l.head
+	l.head = object
+	l.len++
+
+	l.mu.Unlock()
+}
+
+// Len returns the number of idle objects.
+func (l *List[P]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.len
+}
+
+// Inspect calls fn for each idle object, from the most recently put to the oldest,
+// while holding the lock of the list. It is intended for tests and drain logic.
+// Be careful, fn must not call the list, or modify the [Hook].
+func (l *List[P]) Inspect(fn func(object P)) {
+	var zero P
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for object := l.head; object != zero; object = object.FreeListHook().next {
+		fn(object)
+	}
+}
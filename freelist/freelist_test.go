@@ -0,0 +1,141 @@
+package freelist_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/freelist"
+)
+
+type node struct {
+	freelist.Hook[*node]
+	value int
+}
+
+func newNode() *node {
+	return new(node)
+}
+
+var _ xpool.Pool[*node] = (*freelist.List[*node])(nil)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	nodes := freelist.New(newNode)
+
+	first, second := nodes.Get(), nodes.Get()
+	require.NotSame(t, first, second)
+	require.Zero(t, nodes.Len())
+
+	nodes.Put(first)
+	nodes.Put(second)
+	nodes.Put(nil) // ignored
+	assert.Equal(t, 2, nodes.Len())
+
+	assert.Same(t, second, nodes.Get(), "must be LIFO")
+	assert.Same(t, first, nodes.Get())
+	assert.Zero(t, nodes.Len())
+
+	assert.NotSame(t, first, nodes.Get(), "must create when empty")
+}
+
+func TestListConcurrency(t *testing.T) {
+	t.Parallel()
+
+	nodes := freelist.New(newNode)
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 1000; i++ {
+				n := nodes.Get()
+				n.value = g
+				nodes.Put(n)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	assert.LessOrEqual(t, nodes.Len(), 8)
+}
+
+func BenchmarkList(b *testing.B) {
+	benchmarkPool(b, freelist.New(newNode))
+}
+
+func BenchmarkSyncPool(b *testing.B) {
+	benchmarkPool(b, xpool.New(newNode))
+}
+
+func benchmarkPool(b *testing.B, pool xpool.Pool[*node]) {
+	b.Helper()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		n := pool.Get()
+		n.value = i
+		pool.Put(n)
+	}
+}
+
+func TestListInspect(t *testing.T) {
+	t.Parallel()
+
+	nodes := freelist.New(newNode)
+
+	first, second := nodes.Get(), nodes.Get()
+	nodes.Put(first)
+	nodes.Put(second)
+
+	var seen []*node
+
+	nodes.Inspect(func(n *node) {
+		seen = append(seen, n)
+	})
+
+	require.Len(t, seen, 2)
+	assert.Same(t, second, seen[0], "must be from the most recently put")
+	assert.Same(t, first, seen[1])
+	assert.Equal(t, 2, nodes.Len())
+}
+
+func TestWithDuplicateCheck(t *testing.T) {
+	t.Parallel()
+
+	var duplicates []*node
+
+	nodes := freelist.New(newNode, freelist.WithDuplicateCheck(func(n *node) {
+		duplicates = append(duplicates, n)
+	}))
+
+	first, second := nodes.Get(), nodes.Get()
+
+	nodes.Put(first)
+	nodes.Put(first) // the tail
+	nodes.Put(second)
+	nodes.Put(first) // linked to the next one
+	nodes.Put(second)
+
+	assert.Equal(t, []*node{first, first, second}, duplicates)
+	assert.Equal(t, 2, nodes.Len(), "must not store the duplicates")
+
+	assert.Same(t, second, nodes.Get())
+	assert.Same(t, first, nodes.Get())
+
+	nodes.Put(first) // not idle anymore
+	assert.Len(t, duplicates, 3)
+
+	assert.Panics(t, func() {
+		freelist.WithDuplicateCheck[*node](nil)
+	}, "must panic")
+}
@@ -0,0 +1,33 @@
+package freelist
+
+// Option is a functional option to customize a [List].
+// It is parameterized on the same generic type P of the [List].
+type Option[P any] func(*options[P])
+
+type options[P any] struct {
+	onDuplicate func(object P)
+}
+
+func buildOptions[P any](opts []Option[P]) *options[P] {
+	o := &options[P]{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithDuplicateCheck is a debug option that detects the same object put back twice while it is idle,
+// a symptom of a double Put that corrupts the list, calling onDuplicate immediately.
+// The duplicate is not stored. The callback may panic. The check is cheap, it only looks at the [Hook].
+// Will panic if onDuplicate is nil.
+func WithDuplicateCheck[P any](onDuplicate func(object P)) Option[P] {
+	if onDuplicate == nil {
+		panic("callback 'onDuplicate' must not be nil")
+	}
+
+	return func(o *options[P]) {
+		o.onDuplicate = onDuplicate
+	}
+}
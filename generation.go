@@ -0,0 +1,23 @@
+package xpool
+
+import "sync/atomic"
+
+// Generation invalidates the objects of a [Pool], enabled via [WithGeneration].
+// The zero value is ready to use and it is safe for concurrent use.
+type Generation struct {
+	value uint64
+}
+
+// Invalidate bumps the generation: all objects created before, idle or checked out,
+// are discarded on their next Get or Put instead being reused.
+func (g *Generation) Invalidate() {
+	atomic.AddUint64(&g.value, 1)
+}
+
+func (g *Generation) current() uint64 {
+	if g == nil {
+		return 0
+	}
+
+	return atomic.LoadUint64(&g.value)
+}
@@ -0,0 +1,62 @@
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithGeneration(t *testing.T) {
+	t.Parallel()
+
+	var (
+		generation xpool.Generation
+		discarded  []*bytes.Buffer
+	)
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithGeneration[*bytes.Buffer](&generation),
+		xpool.WithHotTier[*bytes.Buffer](1), // the sync.Pool may drop the idle object
+		xpool.WithOnDiscard(func(b *bytes.Buffer) {
+			discarded = append(discarded, b)
+		}))
+
+	idle, checkedOut := pool.Get(), pool.Get()
+	pool.Put(idle)
+
+	generation.Invalidate()
+
+	pool.Put(checkedOut) // must be discarded on put
+	require.Equal(t, []*bytes.Buffer{checkedOut}, discarded)
+
+	fresh := pool.Get() // the idle one must be discarded on get
+	assert.NotSame(t, idle, fresh)
+	assert.NotSame(t, checkedOut, fresh)
+	assert.Equal(t, []*bytes.Buffer{checkedOut, idle}, discarded)
+
+	pool.Put(fresh)
+
+	again := pool.Get()
+	require.Same(t, fresh, again)
+
+	for _, b := range discarded {
+		assert.NotSame(t, fresh, b, "must reuse objects of the current generation")
+	}
+}
+
+func TestWithGenerationNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithGeneration[*bytes.Buffer](nil)
+	}, "must panic")
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		xpool.WithGeneration[[]byte](new(xpool.Generation))
+	}, "must panic if T is not comparable")
+}
@@ -0,0 +1,136 @@
+// Package hashpool offers ready-made pools of [hash.Hash], including keyed HMAC.
+//
+// For regular hashes, the object is resetted before put back to the pool:
+//
+//	pool := hashpool.New(sha256.New)
+//
+//	h := pool.Get()
+//	defer pool.Put(h)
+//
+// For HMAC, the monadic state is the key, wiped before put back to the pool:
+//
+//	pool := hashpool.NewHMAC(sha256.New)
+//
+//	mac := pool.Get(key)
+//	defer pool.Put(mac)
+//
+//	mac.Write(message)
+//	ok := hmac.Equal(mac.Sum(nil), expected)
+package hashpool
+
+import (
+	"hash"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/monadic"
+)
+
+// New returns a pool of [hash.Hash], resetted before put back to the pool.
+// The behavior can be customized via [xpool.Option].
+func New(
+	ctor func() hash.Hash,
+	opts ...xpool.Option[hash.Hash],
+) xpool.Pool[hash.Hash] {
+	return xpool.NewWithResetter(ctor, opts...)
+}
+
+// NewHMAC returns a monadic pool of [HMAC], using the given hash constructor, like [crypto/hmac.New].
+// The state is the key, the key material is wiped before put back to the pool.
+// The behavior can be customized via [monadic.Option].
+func NewHMAC(
+	h func() hash.Hash,
+	opts ...monadic.Option[[]byte, *HMAC],
+) monadic.Pool[[]byte, *HMAC] {
+	return monadic.NewWithCustomResetter(func() *HMAC {
+		return newHMAC(h)
+	}, (*HMAC).SetKey, opts...)
+}
+
+// HMAC is a keyed-hash message authentication code (RFC 2104) that can be rekeyed, via SetKey.
+// It implements [hash.Hash] and produces the same result of [crypto/hmac.New].
+type HMAC struct {
+	inner hash.Hash
+	outer hash.Hash
+	ipad  []byte
+	opad  []byte
+}
+
+func newHMAC(h func() hash.Hash) *HMAC {
+	inner, outer := h(), h()
+
+	blockSize := inner.BlockSize()
+
+	return &HMAC{
+		inner: inner,
+		outer: outer,
+		ipad:  make([]byte, blockSize),
+		opad:  make([]byte, blockSize),
+	}
+}
+
+// SetKey discards any state, including the one derived from the previous key, and set a new key.
+func (m *HMAC) SetKey(key []byte) {
+	if len(key) > len(m.ipad) {
+		// keys longer than the block size are hashed first.
+		m.outer.Reset()
+		m.outer.Write(key)
+
+		key = m.outer.Sum(nil)
+	}
+
+	for i := range m.ipad {
+		m.ipad[i] = 0
+		m.opad[i] = 0
+	}
+
+	copy(m.ipad, key)
+	copy(m.opad, key)
+
+	for i := range m.ipad {
+		m.ipad[i] ^= 0x36
+		m.opad[i] ^= 0x5c
+	}
+
+	m.Reset()
+}
+
+// Write adds more data to the running hash. It never returns an error.
+func (m *HMAC) Write(p []byte) (int, error) {
+	return m.inner.Write(p)
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (m *HMAC) Sum(b []byte) []byte {
+	origLen := len(b)
+
+	b = m.inner.Sum(b)
+
+	m.outer.Reset()
+	m.outer.Write(m.opad)
+	m.outer.Write(b[origLen:])
+
+	b = m.outer.Sum(b[:origLen])
+
+	// the outer hash is only needed during Sum, do not retain state derived from the key.
+	m.outer.Reset()
+
+	return b
+}
+
+// Reset resets the Hash to its initial state, keeping the current key.
+func (m *HMAC) Reset() {
+	m.inner.Reset()
+	m.inner.Write(m.ipad)
+	m.outer.Reset()
+}
+
+// Size returns the number of bytes Sum will return.
+func (m *HMAC) Size() int {
+	return m.outer.Size()
+}
+
+// BlockSize returns the hash's underlying block size.
+func (m *HMAC) BlockSize() int {
+	return m.inner.BlockSize()
+}
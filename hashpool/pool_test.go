@@ -0,0 +1,170 @@
+package hashpool_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/hashpool"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	pool := hashpool.New(sha256.New)
+
+	f := func(p []byte) bool {
+		h := pool.Get()
+		defer pool.Put(h)
+
+		_, _ = h.Write(p)
+
+		expected := sha256.Sum256(p)
+
+		return bytes.Equal(expected[:], h.Sum(nil))
+	}
+
+	err := quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestNewHMAC(t *testing.T) {
+	t.Parallel()
+
+	for _, ctor := range []func() hash.Hash{sha1.New, sha256.New, sha512.New} {
+		ctor := ctor
+		pool := hashpool.NewHMAC(ctor)
+
+		t.Run(fmt.Sprintf("%T", ctor()), func(t *testing.T) {
+			t.Parallel()
+
+			f := func(key, message, prefix []byte) bool {
+				mac := pool.Get(key)
+				defer pool.Put(mac)
+
+				reference := hmac.New(ctor, key)
+
+				prefix = prefix[:len(prefix):len(prefix)] // force append to copy
+
+				_, _ = mac.Write(message)
+				_, _ = reference.Write(message)
+
+				if !bytes.Equal(reference.Sum(prefix), mac.Sum(prefix)) {
+					return false
+				}
+
+				// Reset must keep the key
+				mac.Reset()
+				reference.Reset()
+
+				_, _ = mac.Write(message)
+				_, _ = reference.Write(message)
+
+				return hmac.Equal(reference.Sum(nil), mac.Sum(nil)) &&
+					mac.Size() == reference.Size() &&
+					mac.BlockSize() == reference.BlockSize()
+			}
+
+			err := quick.Check(f, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewHMACLongKey(t *testing.T) {
+	t.Parallel()
+
+	pool := hashpool.NewHMAC(sha256.New)
+
+	key := bytes.Repeat([]byte("k"), 200) // longer than the block size
+
+	mac := pool.Get(key)
+	defer pool.Put(mac)
+
+	reference := hmac.New(sha256.New, key)
+
+	_, _ = mac.Write([]byte("payload"))
+	_, _ = reference.Write([]byte("payload"))
+
+	assert.Equal(t, reference.Sum(nil), mac.Sum(nil))
+}
+
+func TestNewHMACWipesKeyOnPut(t *testing.T) {
+	t.Parallel()
+
+	pool := hashpool.NewHMAC(sha256.New)
+
+	mac := pool.Get([]byte("secret"))
+	pool.Put(mac)
+
+	_, _ = mac.Write([]byte("payload"))
+
+	reference := hmac.New(sha256.New, nil)
+	_, _ = reference.Write([]byte("payload"))
+
+	assert.Equal(t, reference.Sum(nil), mac.Sum(nil), "must be keyed with an empty key")
+}
+
+// recordingHash records the data written since the last Reset, the state that a pooled hash retains.
+type recordingHash struct {
+	hash.Hash
+	written []byte
+}
+
+func (h *recordingHash) Write(p []byte) (int, error) {
+	h.written = append(h.written, p...)
+
+	return h.Hash.Write(p)
+}
+
+func (h *recordingHash) Reset() {
+	h.written = h.written[:0]
+	h.Hash.Reset()
+}
+
+func TestNewHMACRetainsNoKeyStateOnPut(t *testing.T) {
+	t.Parallel()
+
+	for name, key := range map[string][]byte{
+		"short key": []byte("secret"),
+		"long key":  bytes.Repeat([]byte("secret"), 32), // hashed first, via the outer hash
+	} {
+		key := key
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var hashes []*recordingHash
+
+			pool := hashpool.NewHMAC(func() hash.Hash {
+				h := &recordingHash{Hash: sha256.New()}
+				hashes = append(hashes, h)
+
+				return h
+			})
+
+			mac := pool.Get(key)
+			_, _ = mac.Write([]byte("payload"))
+			_ = mac.Sum(nil)
+
+			pool.Put(mac)
+
+			require.Len(t, hashes, 2)
+
+			inner, outer := hashes[0], hashes[1]
+
+			// the empty key of the put state: only the ipad of a zero key.
+			assert.Equal(t, bytes.Repeat([]byte{0x36}, inner.BlockSize()), inner.written)
+			assert.Empty(t, outer.written, "must not retain the outer state of the old key")
+		})
+	}
+}
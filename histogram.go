@@ -0,0 +1,105 @@
+package xpool
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+)
+
+// Histogram counts observations on buckets with fixed upper bounds, like the hold times of the objects,
+// see [WithHoldTimeHistogram]. The layout follows the Prometheus and OpenTelemetry explicit bucket histograms,
+// so the [HistogramSnapshot] maps directly to the exporters. It is safe for concurrent use.
+type Histogram struct {
+	bounds []float64
+	counts []uint64 // len(bounds)+1, the last one is the overflow bucket.
+	count  uint64
+	sum    uint64 // float64 bits
+}
+
+// HistogramSnapshot is a point-in-time copy of the [Histogram] counters.
+type HistogramSnapshot struct {
+	// Bounds are the upper bounds, inclusive, of the buckets, in ascending order.
+	Bounds []float64
+	// Counts are the number of observations on each bucket, not cumulative.
+	// It has one more element than Bounds, the observations greater than the last bound.
+	Counts []uint64
+	// Count is the total number of observations.
+	Count uint64
+	// Sum is the sum of all observations.
+	Sum float64
+}
+
+// NewHistogram returns a [Histogram] with the given upper bounds, see [ExponentialBounds].
+// Will panic if bounds is empty or if it is not in strictly ascending order.
+func NewHistogram(bounds ...float64) *Histogram {
+	if len(bounds) == 0 {
+		panic("argument 'bounds' must not be empty")
+	}
+
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i] <= bounds[i-1] {
+			panic("argument 'bounds' must be in strictly ascending order")
+		}
+	}
+
+	return &Histogram{
+		bounds: append([]float64(nil), bounds...),
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// ExponentialBounds returns count bounds, where the first is start and each one is factor times the previous one.
+// Will panic if start is not positive, if factor is not greater than 1 or if count is not positive.
+func ExponentialBounds(start, factor float64, count int) []float64 {
+	if start <= 0 {
+		panic("argument 'start' must be positive")
+	}
+
+	if factor <= 1 {
+		panic("argument 'factor' must be greater than 1")
+	}
+
+	if count <= 0 {
+		panic("argument 'count' must be positive")
+	}
+
+	bounds := make([]float64, count)
+	for i := range bounds {
+		bounds[i] = start
+		start *= factor
+	}
+
+	return bounds
+}
+
+// Observe adds one observation.
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.bounds, value) // the first bound greater or equal to value.
+
+	atomic.AddUint64(&h.counts[i], 1)
+	atomic.AddUint64(&h.count, 1)
+
+	for {
+		old := atomic.LoadUint64(&h.sum)
+		if atomic.CompareAndSwapUint64(&h.sum, old, math.Float64bits(math.Float64frombits(old)+value)) {
+			return
+		}
+	}
+}
+
+// Snapshot returns a copy of the current counters.
+// The counters are loaded one by one, so the Count may be slightly off of the sum of Counts under concurrent use.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	snapshot := HistogramSnapshot{
+		Bounds: append([]float64(nil), h.bounds...),
+		Counts: make([]uint64, len(h.counts)),
+		Count:  atomic.LoadUint64(&h.count),
+		Sum:    math.Float64frombits(atomic.LoadUint64(&h.sum)),
+	}
+
+	for i := range h.counts {
+		snapshot.Counts[i] = atomic.LoadUint64(&h.counts[i])
+	}
+
+	return snapshot
+}
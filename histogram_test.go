@@ -0,0 +1,78 @@
+package xpool_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Parallel()
+
+	h := xpool.NewHistogram(1, 2, 4)
+
+	for _, v := range []float64{0.5, 1, 1.5, 3, 4, 10} {
+		h.Observe(v)
+	}
+
+	snapshot := h.Snapshot()
+
+	assert.Equal(t, []float64{1, 2, 4}, snapshot.Bounds)
+	assert.Equal(t, []uint64{2, 1, 2, 1}, snapshot.Counts)
+	assert.Equal(t, uint64(6), snapshot.Count)
+	assert.InDelta(t, 20.0, snapshot.Sum, 1e-9)
+}
+
+func TestHistogramConcurrent(t *testing.T) {
+	t.Parallel()
+
+	h := xpool.NewHistogram(xpool.ExponentialBounds(1, 2, 4)...)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				h.Observe(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	snapshot := h.Snapshot()
+
+	assert.Equal(t, uint64(800), snapshot.Count)
+	assert.Equal(t, uint64(800), snapshot.Counts[0])
+	assert.InDelta(t, 800.0, snapshot.Sum, 1e-9)
+}
+
+func TestExponentialBounds(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []float64{1, 4, 16}, xpool.ExponentialBounds(1, 4, 3))
+	assert.Len(t, xpool.DefaultHoldTimeBounds, 10)
+
+	assert.Panics(t, func() { xpool.ExponentialBounds(0, 2, 1) })
+	assert.Panics(t, func() { xpool.ExponentialBounds(1, 1, 1) })
+	assert.Panics(t, func() { xpool.ExponentialBounds(1, 2, 0) })
+}
+
+func TestNewHistogramInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'bounds' must not be empty", func() {
+		xpool.NewHistogram()
+	})
+
+	assert.PanicsWithValue(t, "argument 'bounds' must be in strictly ascending order", func() {
+		xpool.NewHistogram(1, 1)
+	})
+}
@@ -0,0 +1,97 @@
+package xpool
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultHoldTimeBounds are the bounds, in seconds, suggested for [WithHoldTimeHistogram],
+// from 100 microseconds to about 26 seconds.
+var DefaultHoldTimeBounds = ExponentialBounds(0.0001, 4, 10)
+
+// heldPool records how long each object stays checked out, from Get to Put or Discard.
+// The objects are indexed by address, so the pool does not keep them reachable.
+type heldPool[T any] struct {
+	pool      basePool[T]
+	histogram *Histogram
+	clock     Clock
+
+	mu       sync.Mutex
+	checkOut map[uintptr]time.Time
+}
+
+func newHeldPool[T any](pool basePool[T], o *options[T]) basePool[T] {
+	if o.holdTimes == nil {
+		return pool
+	}
+
+	return &heldPool[T]{
+		pool:      pool,
+		histogram: o.holdTimes,
+		clock:     o.getClock(),
+		checkOut:  make(map[uintptr]time.Time),
+	}
+}
+
+func (p *heldPool[T]) Get() T {
+	object := p.pool.Get()
+
+	p.hold(object)
+
+	return object
+}
+
+func (p *heldPool[T]) GetContext(ctx context.Context) (T, error) {
+	object, err := p.pool.GetContext(ctx)
+	if err == nil {
+		p.hold(object)
+	}
+
+	return object, err
+}
+
+func (p *heldPool[T]) Put(object T) {
+	p.release(object)
+
+	p.pool.Put(object)
+}
+
+func (p *heldPool[T]) resetOnPut(object T) error {
+	return resetOnPut[T](p.pool, object)
+}
+
+func (p *heldPool[T]) Discard(object T) {
+	p.release(object)
+
+	p.pool.Discard(object)
+}
+
+func (p *heldPool[T]) hold(object T) {
+	address := uintptr(pointerOf(object))
+	if address == 0 {
+		return
+	}
+
+	now := p.clock.Now()
+
+	p.mu.Lock()
+	p.checkOut[address] = now
+	p.mu.Unlock()
+}
+
+func (p *heldPool[T]) release(object T) {
+	address := uintptr(pointerOf(object))
+	if address == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	since, ok := p.checkOut[address]
+	delete(p.checkOut, address)
+	p.mu.Unlock()
+
+	if ok {
+		p.histogram.Observe(p.clock.Now().Sub(since).Seconds())
+	}
+}
@@ -0,0 +1,65 @@
+package xpool_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+func TestWithHoldTimeHistogram(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+	histogram := xpool.NewHistogram(0.01, 0.1, 1)
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	},
+		xpool.WithHoldTimeHistogram[*bytes.Buffer](histogram),
+		xpool.WithClock[*bytes.Buffer](clock),
+	)
+
+	buf := pool.Get()
+
+	clock.Advance(50 * time.Millisecond)
+
+	pool.Put(buf)
+
+	buf, err := xpool.GetContext(context.Background(), pool)
+	require.NoError(t, err)
+
+	clock.Advance(2 * time.Second)
+
+	xpool.Discard(pool, buf)
+
+	pool.Put(new(bytes.Buffer)) // never checked out, must be ignored
+
+	snapshot := histogram.Snapshot()
+
+	assert.Equal(t, []uint64{0, 1, 0, 1}, snapshot.Counts)
+	assert.Equal(t, uint64(2), snapshot.Count)
+	assert.InDelta(t, 2.05, snapshot.Sum, 1e-9)
+}
+
+func TestWithHoldTimeHistogramInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'histogram' must not be nil", func() {
+		xpool.WithHoldTimeHistogram[*bytes.Buffer](nil)
+	})
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		xpool.WithHoldTimeHistogram[bytes.Buffer](xpool.NewHistogram(1))
+	})
+
+	assert.PanicsWithValue(t, "argument 'clock' must not be nil", func() {
+		xpool.WithClock[*bytes.Buffer](nil)
+	})
+}
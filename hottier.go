@@ -0,0 +1,51 @@
+package xpool
+
+import "sync"
+
+// hotTier is a two-tier backend of a [Pool]: a small array holding the most recently used objects,
+// that survives the garbage collection, backed by a [sync.Pool] for the overflow. See [WithHotTier].
+type hotTier struct {
+	mu   sync.Mutex
+	hot  []any
+	cold sync.Pool
+}
+
+var _ Pool[any] = (*hotTier)(nil)
+
+func newHotTier(size int) *hotTier {
+	return &hotTier{hot: make([]any, 0, size)}
+}
+
+func (t *hotTier) Get() any {
+	t.mu.Lock()
+
+	if n := len(t.hot); n > 0 {
+		value := t.hot[n-1]
+		t.hot[n-1] = nil
+		t.hot = t.hot[:n-1]
+
+		t.mu.Unlock()
+
+		return value
+	}
+
+	t.mu.Unlock()
+
+	return t.cold.Get()
+}
+
+func (t *hotTier) Put(value any) {
+	t.mu.Lock()
+
+	if len(t.hot) < cap(t.hot) {
+		t.hot = append(t.hot, value)
+
+		t.mu.Unlock()
+
+		return
+	}
+
+	t.mu.Unlock()
+
+	t.cold.Put(value)
+}
@@ -0,0 +1,43 @@
+package xpool_test
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithHotTier(t *testing.T) {
+	t.Parallel()
+
+	var news int
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		news++
+
+		return new(bytes.Buffer)
+	}, xpool.WithHotTier[*bytes.Buffer](2))
+
+	first, second, third := pool.Get(), pool.Get(), pool.Get()
+	pool.Put(first)
+	pool.Put(second)
+	pool.Put(third) // overflow to the sync.Pool
+
+	runtime.GC()
+	runtime.GC() // clears the sync.Pool, but not the hot tier
+
+	assert.Same(t, second, pool.Get(), "must return the most recently used")
+	assert.Same(t, first, pool.Get())
+	assert.Equal(t, 3, news)
+}
+
+func TestWithHotTierInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithHotTier[*bytes.Buffer](0)
+	}, "must panic")
+}
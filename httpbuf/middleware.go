@@ -0,0 +1,147 @@
+// Package httpbuf offers a [net/http] middleware that buffers the whole response into a pooled buffer.
+//
+// Since the body is known before the response is sent, the middleware sets the Content-Length,
+// and the callback set via [WithBeforeFlush] can take decisions that depend on the whole body,
+// like an ETag or the compression:
+//
+//	handler := httpbuf.Middleware(httpbuf.WithBeforeFlush(func(w *httpbuf.ResponseWriter, r *http.Request) {
+//	  sum := sha256.Sum256(w.Body().Bytes())
+//	  etag := `"` + hex.EncodeToString(sum[:]) + `"`
+//
+//	  w.Header().Set("ETag", etag)
+//
+//	  if r.Header.Get("If-None-Match") == etag {
+//	    w.WriteHeader(http.StatusNotModified)
+//	    w.Body().Reset()
+//	  }
+//	}))(mux)
+//
+// If the handler panics, nothing is sent and the buffer is put back to the pool.
+// The buffering defeats the streaming, so the [ResponseWriter] does not implement [http.Flusher],
+// do not use it with server-sent events or long polling.
+package httpbuf
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/bufpool"
+)
+
+// Middleware returns a middleware that wraps the handlers with a [ResponseWriter] backed by a pooled buffer,
+// sending the buffered response after the handler returns, and then putting the buffer back to the pool.
+// The behavior can be customized via [Option].
+func Middleware(opts ...Option) func(next http.Handler) http.Handler {
+	o := buildOptions(opts)
+
+	buffers := bufpool.NewBufferPool(o.maxBufferCap)
+
+	return func(next http.Handler) http.Handler {
+		return &handler{
+			next:        next,
+			buffers:     buffers,
+			beforeFlush: o.beforeFlush,
+		}
+	}
+}
+
+type handler struct {
+	next        http.Handler
+	buffers     xpool.Pool[*bytes.Buffer]
+	beforeFlush func(w *ResponseWriter, r *http.Request)
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	buf := h.buffers.Get()
+	defer h.buffers.Put(buf)
+
+	bw := &ResponseWriter{w: w, buf: buf}
+
+	h.next.ServeHTTP(bw, r)
+
+	if h.beforeFlush != nil {
+		bw.flushing = true
+
+		h.beforeFlush(bw, r)
+	}
+
+	bw.flush()
+}
+
+// ResponseWriter is a [http.ResponseWriter] that buffers the status and the body,
+// sent only after the handler returns. The headers are the ones of the underlying writer.
+type ResponseWriter struct {
+	w        http.ResponseWriter
+	buf      *bytes.Buffer
+	status   int
+	flushing bool
+}
+
+// Header returns the header map of the underlying [http.ResponseWriter].
+func (w *ResponseWriter) Header() http.Header {
+	return w.w.Header()
+}
+
+// Write appends p to the buffered body.
+func (w *ResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.buf.Write(p)
+}
+
+// WriteString appends s to the buffered body.
+func (w *ResponseWriter) WriteString(s string) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.buf.WriteString(s)
+}
+
+// WriteHeader records the status code, sent after the handler returns.
+// Like [http.ResponseWriter], only the first call has effect, except on the callback set via [WithBeforeFlush],
+// that can always override the status. The informational status codes, like 103 Early Hints, are sent immediately.
+func (w *ResponseWriter) WriteHeader(status int) {
+	switch {
+	case status >= 100 && status <= 199:
+		w.w.WriteHeader(status)
+	case w.status == 0 || w.flushing:
+		w.status = status
+	}
+}
+
+// Status returns the status code recorded so far, zero if the handler did not write anything.
+func (w *ResponseWriter) Status() int {
+	return w.status
+}
+
+// Body returns the buffered body. It may be modified, or resetted, before the response is sent.
+// It is put back to the pool after the response is sent, so it must not be retained.
+func (w *ResponseWriter) Body() *bytes.Buffer {
+	return w.buf
+}
+
+func (w *ResponseWriter) flush() {
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	// see RFC 9110, section 6.4.1, there is no body on 204 No Content and 304 Not Modified.
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		w.w.WriteHeader(status)
+
+		return
+	}
+
+	if w.Header().Get("Content-Length") == "" {
+		w.Header().Set("Content-Length", strconv.Itoa(w.buf.Len()))
+	}
+
+	w.w.WriteHeader(status)
+	_, _ = w.w.Write(w.buf.Bytes())
+}
@@ -0,0 +1,133 @@
+package httpbuf_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/httpbuf"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := httpbuf.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusCreated)
+		w.WriteHeader(http.StatusInternalServerError) // ignored, like net/http
+
+		_, _ = w.Write([]byte("hello "))
+		_, _ = w.Write([]byte("world"))
+	}))
+
+	for i := 0; i < 3; i++ {
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "11", rec.Header().Get("Content-Length"))
+		assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "hello world", rec.Body.String())
+	}
+}
+
+func TestMiddlewareEmpty(t *testing.T) {
+	t.Parallel()
+
+	handler := httpbuf.Middleware()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("Content-Length"))
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestMiddlewareNoContent(t *testing.T) {
+	t.Parallel()
+
+	handler := httpbuf.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/", nil))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Length"))
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	t.Parallel()
+
+	handler := httpbuf.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+
+	require.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	assert.False(t, rec.Flushed)
+	assert.Empty(t, rec.Body.String(), "nothing must be sent")
+}
+
+func TestWithBeforeFlush(t *testing.T) {
+	t.Parallel()
+
+	handler := httpbuf.Middleware(
+		httpbuf.WithMaxBufferCap(64),
+		httpbuf.WithBeforeFlush(func(w *httpbuf.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.StatusOK, w.Status())
+
+			etag := `"` + w.Body().String() + `"`
+
+			w.Header().Set("ETag", etag)
+
+			if r.Header.Get("If-None-Match") == etag {
+				w.WriteHeader(http.StatusNotModified)
+				w.Body().Reset()
+			}
+		}),
+	)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("v1"))
+	}))
+
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"v1"`, rec.Header().Get("ETag"))
+	assert.Equal(t, "v1", rec.Body.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", `"v1"`)
+
+	rec = httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
+
+func TestWithBeforeFlushNil(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "callback 'beforeFlush' must not be nil", func() {
+		_ = httpbuf.WithBeforeFlush(nil)
+	})
+}
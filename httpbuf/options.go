@@ -0,0 +1,48 @@
+package httpbuf
+
+import "net/http"
+
+// DefaultMaxBufferCap is the default maximum capacity of the buffers put back to the pool, see [WithMaxBufferCap].
+const DefaultMaxBufferCap = 1 << 20
+
+// Option is a functional option to customize the [Middleware].
+type Option func(*options)
+
+type options struct {
+	maxBufferCap int
+	beforeFlush  func(w *ResponseWriter, r *http.Request)
+}
+
+func buildOptions(opts []Option) *options {
+	o := &options{maxBufferCap: DefaultMaxBufferCap}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithMaxBufferCap sets the maximum capacity of the buffers put back to the pool, the buffers used
+// by bigger responses are dropped, so one huge response does not pin the memory.
+// A maxCap less or equal to zero means no limit. By default, it is [DefaultMaxBufferCap].
+func WithMaxBufferCap(maxCap int) Option {
+	return func(o *options) {
+		o.maxBufferCap = maxCap
+	}
+}
+
+// WithBeforeFlush sets a callback called after the handler, before the response is sent,
+// with the whole body on [ResponseWriter.Body]. It may change the headers, the status and the body,
+// like set an ETag and reply 304 Not Modified, or compress the body and set the Content-Encoding.
+// Be careful, the callback must be thread safe.
+// Will panic if beforeFlush is nil.
+func WithBeforeFlush(beforeFlush func(w *ResponseWriter, r *http.Request)) Option {
+	if beforeFlush == nil {
+		panic("callback 'beforeFlush' must not be nil")
+	}
+
+	return func(o *options) {
+		o.beforeFlush = beforeFlush
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !race
+
+package imagepool_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool/imagepool"
+)
+
+// the sync.Pool drops objects at random under the race detector, so the allocations are only measured without it.
+func TestGetPutAllocs(t *testing.T) {
+	pool := imagepool.New(1 << 20)
+	bounds := image.Rect(0, 0, 64, 64)
+
+	pool.PutRGBA(pool.GetRGBA(bounds)) // warm up
+
+	allocs := testing.AllocsPerRun(100, func() {
+		pool.PutRGBA(pool.GetRGBA(bounds))
+	})
+
+	assert.Zero(t, allocs, "must not allocate on a Get and Put cycle")
+}
@@ -0,0 +1,131 @@
+// Package imagepool offers a pool of images, the pixel buffers are reused across different dimensions.
+//
+//	pool := imagepool.New(64 << 20)
+//
+//	thumb := pool.GetRGBA(image.Rect(0, 0, 320, 240))
+//	defer pool.PutRGBA(thumb)
+//
+// The pixel buffers are stored on a [slicepool.Pool], in size classes, powers of two, so any buffer
+// with a capacity greater or equal to the requested width * height * bytes per pixel is re-sliced
+// to the requested bounds, like a 640x480 buffer reused for a 320x240 image.
+// The images must not be used after put back to the pool, neither their sub-images.
+package imagepool
+
+import (
+	"image"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/slicepool"
+)
+
+// minBufferSize is the first size class, smaller buffers are not worth to pool.
+const minBufferSize = 4 << 10
+
+// Pool is a pool of images, backed by a pool of pixel buffers.
+type Pool struct {
+	pix     *slicepool.Pool[uint8]
+	headers xpool.Pool[*[]uint8] // the empty slice headers, so putPix does not allocate one for the pool.
+}
+
+// New returns a [Pool] that keeps pixel buffers up to maxBufferSize bytes, rounded up to a power of two.
+// The images with bigger pixel buffers are allocated on demand and dropped on Put.
+// Will panic if maxBufferSize is not positive.
+func New(maxBufferSize int) *Pool {
+	if maxBufferSize <= 0 {
+		panic("argument 'maxBufferSize' must be positive")
+	}
+
+	if maxBufferSize < minBufferSize {
+		maxBufferSize = minBufferSize
+	}
+
+	return &Pool{
+		pix: slicepool.New[uint8](minBufferSize, maxBufferSize),
+		headers: xpool.New(func() *[]uint8 {
+			return new([]uint8)
+		}),
+	}
+}
+
+// GetRGBA fetch an [image.RGBA] with the given bounds from the pool, like [image.NewRGBA].
+// The pixels are zeroed, so the image is fully transparent.
+func (p *Pool) GetRGBA(r image.Rectangle) *image.RGBA {
+	return &image.RGBA{Pix: p.getPix(r, 4), Stride: 4 * r.Dx(), Rect: r}
+}
+
+// PutRGBA return the pixel buffer of the image to the pool. Ignores nil images.
+func (p *Pool) PutRGBA(img *image.RGBA) {
+	if img == nil {
+		return
+	}
+
+	p.putPix(img.Pix)
+
+	*img = image.RGBA{}
+}
+
+// GetNRGBA fetch an [image.NRGBA] with the given bounds from the pool, like [image.NewNRGBA].
+// The pixels are zeroed, so the image is fully transparent.
+func (p *Pool) GetNRGBA(r image.Rectangle) *image.NRGBA {
+	return &image.NRGBA{Pix: p.getPix(r, 4), Stride: 4 * r.Dx(), Rect: r}
+}
+
+// PutNRGBA return the pixel buffer of the image to the pool. Ignores nil images.
+func (p *Pool) PutNRGBA(img *image.NRGBA) {
+	if img == nil {
+		return
+	}
+
+	p.putPix(img.Pix)
+
+	*img = image.NRGBA{}
+}
+
+// GetGray fetch an [image.Gray] with the given bounds from the pool, like [image.NewGray].
+// The pixels are zeroed, so the image is black.
+func (p *Pool) GetGray(r image.Rectangle) *image.Gray {
+	return &image.Gray{Pix: p.getPix(r, 1), Stride: r.Dx(), Rect: r}
+}
+
+// PutGray return the pixel buffer of the image to the pool. Ignores nil images.
+func (p *Pool) PutGray(img *image.Gray) {
+	if img == nil {
+		return
+	}
+
+	p.putPix(img.Pix)
+
+	*img = image.Gray{}
+}
+
+func (p *Pool) getPix(r image.Rectangle, bytesPerPixel int) []uint8 {
+	w, h := r.Dx(), r.Dy()
+
+	// same validation of image.NewRGBA, including the overflow.
+	size := w * h * bytesPerPixel
+	if w < 0 || h < 0 || (w != 0 && size/w != h*bytesPerPixel) {
+		panic("imagepool: rectangle has huge or negative dimensions")
+	}
+
+	header := p.pix.Get(size)
+
+	pix := *header
+	*header = nil
+	p.headers.Put(header)
+
+	for i := range pix {
+		pix[i] = 0
+	}
+
+	return pix
+}
+
+func (p *Pool) putPix(pix []uint8) {
+	if cap(pix) == 0 {
+		return
+	}
+
+	header := p.headers.Get()
+	*header = pix
+	p.pix.Put(header)
+}
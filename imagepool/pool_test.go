@@ -0,0 +1,132 @@
+package imagepool_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/imagepool"
+)
+
+func TestGetRGBA(t *testing.T) {
+	t.Parallel()
+
+	pool := imagepool.New(1 << 20)
+
+	r := image.Rect(10, 20, 330, 260)
+
+	img := pool.GetRGBA(r)
+
+	expected := image.NewRGBA(r)
+
+	assert.Equal(t, expected.Rect, img.Rect)
+	assert.Equal(t, expected.Stride, img.Stride)
+	assert.Len(t, img.Pix, len(expected.Pix))
+
+	img.Set(10, 20, color.White)
+	assert.Equal(t, color.RGBAModel.Convert(color.White), img.At(10, 20))
+
+	pool.PutRGBA(img)
+
+	assert.Nil(t, img.Pix, "must not be used after put")
+}
+
+func TestGetRGBAReuse(t *testing.T) {
+	t.Parallel()
+
+	pool := imagepool.New(1 << 20)
+
+	for i := 0; i < 100; i++ {
+		big := pool.GetRGBA(image.Rect(0, 0, 64, 64))
+		pix := &big.Pix[:1][0]
+
+		big.Set(0, 0, color.White)
+
+		pool.PutRGBA(big)
+
+		small := pool.GetRGBA(image.Rect(0, 0, 40, 30))
+		if pix == &small.Pix[:1][0] {
+			assert.Len(t, small.Pix, 4*40*30, "must be re-sliced")
+			assert.Equal(t, 4*40, small.Stride)
+			assert.Equal(t, color.RGBA{}, small.At(0, 0), "must be zeroed")
+
+			pool.PutRGBA(small)
+
+			return
+		}
+
+		pool.PutRGBA(small)
+	}
+
+	t.Fatal("the pool never reused the larger pixel buffer")
+}
+
+func TestGetNRGBA(t *testing.T) {
+	t.Parallel()
+
+	pool := imagepool.New(1 << 20)
+
+	r := image.Rect(0, 0, 100, 50)
+
+	img := pool.GetNRGBA(r)
+	require.NotNil(t, img)
+
+	expected := image.NewNRGBA(r)
+
+	assert.Equal(t, expected.Rect, img.Rect)
+	assert.Equal(t, expected.Stride, img.Stride)
+	assert.Equal(t, expected.Pix, img.Pix)
+
+	pool.PutNRGBA(img)
+	pool.PutNRGBA(nil)
+}
+
+func TestGetGray(t *testing.T) {
+	t.Parallel()
+
+	pool := imagepool.New(1 << 20)
+
+	r := image.Rect(0, 0, 100, 50)
+
+	img := pool.GetGray(r)
+	require.NotNil(t, img)
+
+	expected := image.NewGray(r)
+
+	assert.Equal(t, expected.Rect, img.Rect)
+	assert.Equal(t, expected.Stride, img.Stride)
+	assert.Equal(t, expected.Pix, img.Pix)
+
+	pool.PutGray(img)
+	pool.PutGray(nil)
+}
+
+func TestOversized(t *testing.T) {
+	t.Parallel()
+
+	pool := imagepool.New(1)
+
+	img := pool.GetRGBA(image.Rect(0, 0, 1000, 1000))
+	assert.Len(t, img.Pix, 4*1000*1000)
+
+	pool.PutRGBA(img) // must be dropped
+	pool.PutRGBA(nil)
+	pool.PutRGBA(&image.RGBA{})
+}
+
+func TestInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'maxBufferSize' must be positive", func() {
+		imagepool.New(0)
+	})
+
+	pool := imagepool.New(1 << 20)
+
+	assert.Panics(t, func() {
+		pool.GetRGBA(image.Rectangle{Min: image.Pt(10, 10)})
+	})
+}
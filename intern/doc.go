@@ -0,0 +1,9 @@
+// Package intern deduplicates immutable comparable values, like strings parsed from a payload,
+// so equal values share the same memory. It complements the object pooling for string-heavy parsers.
+//
+//	name := intern.Intern(string(field)) // equal names share the same backing array
+//
+// On Go 1.23 or later it wraps [unique.Make], where the interned values are reclaimed by the
+// garbage collection when they are not used anymore. On older versions, it falls back to a global map,
+// where the interned values are never reclaimed, so it should be used only for a bounded set of values.
+package intern
@@ -0,0 +1,10 @@
+//go:build go1.23
+
+package intern
+
+import "unique"
+
+// Intern returns a canonical copy of v: all calls with equal values return the same copy.
+func Intern[T comparable](v T) T {
+	return unique.Make(v).Value()
+}
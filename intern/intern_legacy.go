@@ -0,0 +1,20 @@
+//go:build !go1.23
+
+package intern
+
+import "sync"
+
+// values holds the canonical copies, before Go 1.23 they are never reclaimed.
+var values sync.Map
+
+// Intern returns a canonical copy of v: all calls with equal values return the same copy.
+func Intern[T comparable](v T) T {
+	value, ok := values.Load(v)
+	if !ok {
+		value, _ = values.LoadOrStore(v, v)
+	}
+
+	canonical, _ := value.(T)
+
+	return canonical
+}
@@ -0,0 +1,39 @@
+package intern_test
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool/intern"
+)
+
+func TestIntern(t *testing.T) {
+	t.Parallel()
+
+	first := intern.Intern(strings.Repeat("a", 64))
+	second := intern.Intern(strings.Repeat("a", 64))
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, dataOf(first), dataOf(second), "must share the same memory")
+
+	other := intern.Intern(strings.Repeat("b", 64))
+	assert.NotEqual(t, dataOf(first), dataOf(other))
+}
+
+// dataOf returns the address of the backing array of the string.
+func dataOf(s string) uintptr {
+	return *(*uintptr)(unsafe.Pointer(&s))
+}
+
+type point struct {
+	x, y int
+}
+
+func TestInternStruct(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, point{1, 2}, intern.Intern(point{1, 2}))
+}
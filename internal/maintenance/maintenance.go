@@ -0,0 +1,60 @@
+// Package maintenance runs the periodic background tasks of the pools of this module,
+// like the eviction of idle objects.
+package maintenance
+
+import (
+	"sync"
+	"time"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Maintenance runs a task periodically on a background goroutine, until Stop is called.
+type Maintenance struct {
+	stopOnce sync.Once
+	stopping chan struct{}
+	done     chan struct{}
+}
+
+// Start runs task every interval, measured by clock, on a new goroutine.
+func Start(clock xpool.Clock, interval time.Duration, task func(now time.Time)) *Maintenance {
+	m := &Maintenance{
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go m.loop(clock, interval, task)
+
+	return m
+}
+
+func (m *Maintenance) loop(clock xpool.Clock, interval time.Duration, task func(now time.Time)) {
+	defer close(m.done)
+
+	timer := clock.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case now := <-timer.C():
+			task(now)
+			timer.Reset(interval)
+		case <-m.stopping:
+			return
+		}
+	}
+}
+
+// Stop terminates the goroutine and waits for it. It is safe to call it several times,
+// or on a nil Maintenance.
+func (m *Maintenance) Stop() {
+	if m == nil {
+		return
+	}
+
+	m.stopOnce.Do(func() {
+		close(m.stopping)
+	})
+
+	<-m.done
+}
@@ -0,0 +1,53 @@
+// Package semaphore bounds the number of objects checked out from the pools of this module.
+package semaphore
+
+import "context"
+
+// Semaphore is a counting semaphore backed by a buffered channel.
+// A nil Semaphore never blocks.
+type Semaphore chan struct{}
+
+// New returns a Semaphore of size slots, or nil if size is not positive.
+func New(size int) Semaphore {
+	if size <= 0 {
+		return nil
+	}
+
+	return make(Semaphore, size)
+}
+
+// Acquire blocks until a slot is free or the context is done.
+func (s Semaphore) Acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryAcquire returns false if the semaphore is full, without blocking.
+func (s Semaphore) TryAcquire() bool {
+	if s == nil {
+		return true
+	}
+
+	select {
+	case s <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release never blocks, even if it is called more times than Acquire.
+func (s Semaphore) Release() {
+	select {
+	case <-s:
+	default:
+	}
+}
@@ -0,0 +1,36 @@
+// Package iocopy offers [Copy], an alternative of [io.Copy] that uses a pooled buffer
+// instead allocate a temporary one on each call.
+//
+//	n, err := iocopy.Copy(dst, src)
+//
+// Like [io.Copy], if src implements [io.WriterTo] or dst implements [io.ReaderFrom],
+// the buffer is not used at all.
+package iocopy
+
+import (
+	"io"
+
+	"github.com/peczenyj/xpool"
+)
+
+// BufferSize is the size of the pooled buffers, the same used by [io.Copy].
+const BufferSize = 32 * 1024
+
+// Buffers is the pool of buffers used by [Copy].
+// It stores pointers to slices, to avoid an allocation on each Put.
+// Callers may use it directly, but they must not change the length of the slices.
+var Buffers xpool.Pool[*[]byte] = xpool.New(func() *[]byte {
+	buf := make([]byte, BufferSize)
+
+	return &buf
+})
+
+// Copy copies from src to dst until either EOF is reached on src or an error occurs,
+// like [io.Copy], using a buffer from [Buffers].
+// It returns the number of bytes copied and the first error encountered while copying, if any.
+func Copy(dst io.Writer, src io.Reader) (int64, error) {
+	buf := Buffers.Get()
+	defer Buffers.Put(buf)
+
+	return io.CopyBuffer(dst, src, *buf)
+}
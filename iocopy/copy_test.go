@@ -0,0 +1,70 @@
+package iocopy_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/iocopy"
+)
+
+type writerOnly struct {
+	b bytes.Buffer
+}
+
+func (w *writerOnly) Write(p []byte) (int, error) {
+	return w.b.Write(p)
+}
+
+func TestCopy(t *testing.T) {
+	t.Parallel()
+
+	f := func(payload []byte) bool {
+		var dst writerOnly
+
+		// hide io.WriterTo and io.ReaderFrom to force the usage of the buffer
+		n, err := iocopy.Copy(&dst, iotest.HalfReader(bytes.NewReader(payload)))
+
+		return err == nil && n == int64(len(payload)) && bytes.Equal(payload, dst.b.Bytes())
+	}
+
+	err := quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestCopyError(t *testing.T) {
+	t.Parallel()
+
+	var dst writerOnly
+
+	_, err := iocopy.Copy(&dst, iotest.ErrReader(iotest.ErrTimeout))
+	require.ErrorIs(t, err, iotest.ErrTimeout)
+}
+
+func BenchmarkCopy(b *testing.B) {
+	payload := strings.Repeat("x", 64*1024)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		var dst writerOnly
+
+		dst.b.Grow(len(payload))
+
+		_, _ = iocopy.Copy(&dst, iotest.HalfReader(strings.NewReader(payload)))
+	}
+}
+
+func TestBuffers(t *testing.T) {
+	t.Parallel()
+
+	buf := iocopy.Buffers.Get()
+	defer iocopy.Buffers.Put(buf)
+
+	assert.Len(t, *buf, iocopy.BufferSize)
+}
@@ -0,0 +1,89 @@
+// Package jsonpool offers pooled helpers for [encoding/json].
+//
+// [MarshalPooled] encodes a value into a pooled buffer:
+//
+//	data, release, err := jsonpool.MarshalPooled(v)
+//	if err != nil {
+//	  return err
+//	}
+//	defer release() // data must not be used after release
+//
+// [NewEncoderPool] returns a monadic pool of [Encoder], where the state is the target [io.Writer].
+//
+// There is no pool of [json.Decoder]: it buffers the input internally and it can't be resetted,
+// so reusing it with a different reader may decode stale data.
+package jsonpool
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/monadic"
+)
+
+var buffers = xpool.NewWithResetter(func() *bytes.Buffer {
+	return new(bytes.Buffer)
+})
+
+// MarshalPooled returns the JSON encoding of v, like [json.Marshal], using a pooled buffer.
+// The release function put the buffer back to the pool, the returned bytes must not be used after it.
+func MarshalPooled(v any) ([]byte, func(), error) {
+	buf := buffers.Get()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		buffers.Put(buf)
+
+		return nil, nil, err
+	}
+
+	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n")) // the Encoder adds a newline, Marshal does not.
+
+	return data, func() { buffers.Put(buf) }, nil
+}
+
+// Encoder is a [json.Encoder] that can be resetted to write to a different [io.Writer].
+type Encoder struct {
+	*json.Encoder
+	w *writerRef
+}
+
+// NewEncoder returns a new [Encoder] that writes to w.
+// The optional configure callback may be used to call SetIndent or SetEscapeHTML.
+func NewEncoder(w io.Writer, configure func(*json.Encoder)) *Encoder {
+	ref := &writerRef{w: w}
+
+	enc := json.NewEncoder(ref)
+	if configure != nil {
+		configure(enc)
+	}
+
+	return &Encoder{Encoder: enc, w: ref}
+}
+
+// Reset discards any state and makes the Encoder write to w.
+func (e *Encoder) Reset(w io.Writer) {
+	e.w.w = w
+}
+
+// NewEncoderPool returns a monadic pool of [Encoder].
+// The optional configure callback is called once per Encoder, when it is created, so the
+// settings like SetIndent are the same for all encoders and must not be changed by the caller.
+// The behavior can be customized via [monadic.Option].
+func NewEncoderPool(
+	configure func(*json.Encoder),
+	opts ...monadic.Option[io.Writer, *Encoder],
+) monadic.Pool[io.Writer, *Encoder] {
+	return monadic.New(func() *Encoder {
+		return NewEncoder(nil, configure)
+	}, opts...)
+}
+
+type writerRef struct {
+	w io.Writer
+}
+
+func (r *writerRef) Write(p []byte) (int, error) {
+	return r.w.Write(p)
+}
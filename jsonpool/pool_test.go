@@ -0,0 +1,86 @@
+package jsonpool_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/jsonpool"
+)
+
+func TestMarshalPooled(t *testing.T) {
+	t.Parallel()
+
+	f := func(v map[string][]int, s string) bool {
+		value := struct {
+			V map[string][]int
+			S string `json:"s,omitempty"`
+		}{V: v, S: s}
+
+		expected, err := json.Marshal(value)
+		if err != nil {
+			return false
+		}
+
+		data, release, err := jsonpool.MarshalPooled(value)
+		if err != nil {
+			return false
+		}
+		defer release()
+
+		return bytes.Equal(expected, data)
+	}
+
+	err := quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestMarshalPooledError(t *testing.T) {
+	t.Parallel()
+
+	data, release, err := jsonpool.MarshalPooled(make(chan int))
+	require.Error(t, err)
+	assert.Nil(t, data)
+	assert.Nil(t, release)
+}
+
+func TestEncoderPool(t *testing.T) {
+	t.Parallel()
+
+	pool := jsonpool.NewEncoderPool(func(enc *json.Encoder) {
+		enc.SetEscapeHTML(false)
+	})
+
+	for _, payload := range []string{"<a>", "<b>"} {
+		var b bytes.Buffer
+
+		enc := pool.Get(&b)
+
+		err := enc.Encode(payload)
+		pool.Put(enc)
+
+		require.NoError(t, err)
+		assert.Equal(t, `"`+payload+`"`+"\n", b.String())
+	}
+}
+
+func ExampleNewEncoderPool() {
+	pool := jsonpool.NewEncoderPool(func(enc *json.Encoder) {
+		enc.SetIndent("", "  ")
+	})
+
+	enc := pool.Get(os.Stdout)
+	defer pool.Put(enc)
+
+	_ = enc.Encode(map[string]int{"answer": 42})
+
+	// Output:
+	// {
+	//   "answer": 42
+	// }
+}
@@ -0,0 +1,72 @@
+//go:build go1.24
+
+package xpool
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// lostTracker attaches a cleanup to the objects checked out from the pool, stopped on Put or Discard,
+// so the objects that become unreachable without Put are detected, see [WithLostObjects].
+// The objects are indexed by address, so the tracker does not keep them reachable.
+type lostTracker struct {
+	mu       sync.Mutex
+	entries  map[uintptr]*lostEntry
+	callback func()
+}
+
+type lostEntry struct {
+	tracker *lostTracker
+	address uintptr
+	cleanup runtime.Cleanup
+}
+
+func newLostTracker(callback func()) *lostTracker {
+	return &lostTracker{
+		entries:  make(map[uintptr]*lostEntry),
+		callback: callback,
+	}
+}
+
+func (t *lostTracker) checkOut(object unsafe.Pointer) {
+	if t == nil || object == nil {
+		return
+	}
+
+	e := &lostEntry{tracker: t, address: uintptr(object)}
+	e.cleanup = runtime.AddCleanup((*byte)(object), (*lostEntry).lost, e)
+
+	t.mu.Lock()
+	t.entries[e.address] = e
+	t.mu.Unlock()
+}
+
+func (t *lostTracker) checkIn(object unsafe.Pointer) {
+	if t == nil || object == nil {
+		return
+	}
+
+	t.mu.Lock()
+	e := t.entries[uintptr(object)]
+	delete(t.entries, uintptr(object))
+	t.mu.Unlock()
+
+	if e != nil {
+		e.cleanup.Stop()
+	}
+}
+
+func (e *lostEntry) lost() {
+	t := e.tracker
+
+	t.mu.Lock()
+	// the address may be reused by another object checked out after this one was collected.
+	if t.entries[e.address] == e {
+		delete(t.entries, e.address)
+	}
+	t.mu.Unlock()
+
+	t.callback()
+}
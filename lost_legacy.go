@@ -0,0 +1,16 @@
+//go:build !go1.24
+
+package xpool
+
+import "unsafe"
+
+// lostTracker is a no-op before Go 1.24, since it requires runtime.AddCleanup.
+type lostTracker struct{}
+
+func newLostTracker(func()) *lostTracker {
+	return nil
+}
+
+func (*lostTracker) checkOut(unsafe.Pointer) {}
+
+func (*lostTracker) checkIn(unsafe.Pointer) {}
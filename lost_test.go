@@ -0,0 +1,87 @@
+//go:build go1.24
+
+package xpool_test
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func waitLost(t *testing.T, stats *xpool.Stats, want uint64) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for stats.Snapshot().Lost < want && time.Now().Before(deadline) {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Equal(t, want, stats.Snapshot().Lost)
+}
+
+func TestWithLostObjects(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	},
+		xpool.WithStats[*bytes.Buffer](&stats),
+		xpool.WithLostObjects[*bytes.Buffer](false),
+		xpool.WithMaxInFlight[*bytes.Buffer](1),
+	)
+
+	pool.Put(pool.Get()) // the cleanup of a returned object must be stopped
+
+	pool.Get().WriteString("forgotten") // never put back
+
+	waitLost(t, &stats, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	object, err := xpool.GetContext(ctx, pool)
+	require.NoError(t, err, "must release the in-flight slot of the lost object")
+
+	pool.Put(object)
+
+	runtime.GC()
+	assert.Equal(t, uint64(1), stats.Snapshot().Lost)
+}
+
+func TestWithLostObjectsReplace(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	},
+		xpool.WithStats[*bytes.Buffer](&stats),
+		xpool.WithLostObjects[*bytes.Buffer](true),
+	)
+
+	pool.Get().WriteString("forgotten") // never put back
+
+	waitLost(t, &stats, 1)
+
+	assert.Equal(t, uint64(2), stats.Snapshot().News, "must create a replacement")
+}
+
+func TestWithLostObjectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithLostObjects[bytes.Buffer](false)
+	}, "must panic")
+}
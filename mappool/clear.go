@@ -0,0 +1,7 @@
+//go:build go1.21
+
+package mappool
+
+func clearMap[K comparable, V any](m map[K]V) {
+	clear(m)
+}
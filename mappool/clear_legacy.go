@@ -0,0 +1,9 @@
+//go:build !go1.21
+
+package mappool
+
+func clearMap[K comparable, V any](m map[K]V) {
+	for k := range m {
+		delete(m, k) // optimized by the compiler into a map clear.
+	}
+}
@@ -0,0 +1,68 @@
+// Package mappool offers a pool of maps, cleared before put back to the pool.
+//
+//	pool := mappool.New[string, int](64, 1024)
+//
+//	m := pool.Get()
+//	defer pool.Put(m) // cleared, or dropped if it had more than 1024 entries
+//
+// A map never shrinks: it keeps the buckets allocated for the largest number of
+// entries it ever had, even after being cleared. To avoid that every pooled map converges
+// to the largest size ever seen, maps that grew beyond a limit are dropped, see [MaxLenTrimmer].
+package mappool
+
+import "github.com/peczenyj/xpool"
+
+// Pool is a type-safe pool of maps.
+type Pool[K comparable, V any] struct {
+	pool xpool.Pool[map[K]V]
+}
+
+// New returns a [Pool] of maps created with the size hint.
+// Maps with more than maxLen entries are dropped instead put back to the pool,
+// a maxLen less or equal to zero means no limit.
+// The behavior can be customized via [xpool.Option], like [xpool.WithTrimmer].
+func New[K comparable, V any](
+	sizeHint, maxLen int,
+	opts ...xpool.Option[map[K]V],
+) *Pool[K, V] {
+	if maxLen > 0 {
+		opts = append([]xpool.Option[map[K]V]{xpool.WithTrimmer(MaxLenTrimmer[K, V](maxLen))}, opts...)
+	}
+
+	return &Pool[K, V]{
+		pool: xpool.NewWithCustomResetter(func() map[K]V {
+			return make(map[K]V, sizeHint)
+		}, clearMap[K, V], opts...),
+	}
+}
+
+// Get fetch one empty map from the pool. If needed, will create another map.
+func (p *Pool[K, V]) Get() map[K]V {
+	return p.pool.Get()
+}
+
+// Put clear the map and return it to the pool.
+// The map is dropped if it has more than maxLen entries.
+func (p *Pool[K, V]) Put(m map[K]V) {
+	p.pool.Put(m)
+}
+
+// MaxLenTrimmer returns a [xpool.Trimmer] that drops the maps with more than maxLen entries,
+// see [xpool.WithTrimmer].
+func MaxLenTrimmer[K comparable, V any](maxLen int) xpool.Trimmer[map[K]V] {
+	return xpool.TrimmerFunc[map[K]V](func(m map[K]V) (map[K]V, bool) {
+		return m, len(m) <= maxLen
+	})
+}
+
+// ShrinkingTrimmer returns a [xpool.Trimmer] that replaces the maps with more than maxLen entries
+// by a new map created with the size hint, instead drop them, see [xpool.WithTrimmer].
+func ShrinkingTrimmer[K comparable, V any](sizeHint, maxLen int) xpool.Trimmer[map[K]V] {
+	return xpool.TrimmerFunc[map[K]V](func(m map[K]V) (map[K]V, bool) {
+		if len(m) > maxLen {
+			return make(map[K]V, sizeHint), true
+		}
+
+		return m, true
+	})
+}
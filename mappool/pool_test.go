@@ -0,0 +1,72 @@
+package mappool_test
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/mappool"
+)
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	pool := mappool.New[string, int](8, 0)
+
+	f := func(entries map[string]int) bool {
+		m := pool.Get()
+		defer pool.Put(m)
+
+		if len(m) != 0 {
+			return false
+		}
+
+		for k, v := range entries {
+			m[k] = v
+		}
+
+		return len(m) == len(entries)
+	}
+
+	err := quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestPoolClearOnPut(t *testing.T) {
+	t.Parallel()
+
+	pool := mappool.New[string, int](8, 2)
+
+	m := pool.Get()
+	m["a"] = 1
+
+	pool.Put(m)
+
+	assert.Empty(t, m, "must clear on put")
+
+	large := pool.Get()
+	large["a"], large["b"], large["c"] = 1, 2, 3
+
+	pool.Put(large) // must be dropped
+
+	assert.Len(t, large, 3, "must not clear a dropped map")
+}
+
+func TestShrinkingTrimmer(t *testing.T) {
+	t.Parallel()
+
+	pool := mappool.New(8, 0, xpool.WithTrimmer(mappool.ShrinkingTrimmer[string, int](8, 2)))
+
+	large := pool.Get()
+	large["a"], large["b"], large["c"] = 1, 2, 3
+
+	pool.Put(large) // must be replaced
+
+	assert.Len(t, large, 3, "must not clear a replaced map")
+
+	m := pool.Get()
+	assert.Empty(t, m)
+}
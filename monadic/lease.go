@@ -0,0 +1,35 @@
+package monadic
+
+import (
+	"sync/atomic"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Lease is a handle to an object fetched from a monadic [Pool] via Lease method.
+// It can be safely passed across goroutine boundaries, and Release is idempotent.
+type Lease[T any] struct {
+	object   T
+	put      func(object T)
+	released uint32
+}
+
+// Value returns the leased object.
+// The object must not be used after Release.
+func (l *Lease[T]) Value() T {
+	return l.object
+}
+
+// Release put the leased object back to the pool.
+// Only the first call has effect, it is safe to call it several times.
+func (l *Lease[T]) Release() {
+	if atomic.CompareAndSwapUint32(&l.released, 0, 1) {
+		l.put(l.object)
+	}
+}
+
+// AutoRelease returns an [io.Closer] that put the object back to the monadic pool exactly once,
+// on the first Close, see [xpool.AutoRelease].
+func AutoRelease[S, T any](pool Pool[S, T], object T) *xpool.AutoReleaser[T] {
+	return xpool.AutoRelease[T](pool, object)
+}
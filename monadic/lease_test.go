@@ -0,0 +1,67 @@
+package monadic_test
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/monadic"
+)
+
+func TestLease(t *testing.T) {
+	t.Parallel()
+
+	var stats monadic.Stats
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithStats[[]byte, *bytes.Reader](&stats))
+
+	lease := pool.Lease([]byte(`payload`))
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer lease.Release()
+
+		content, err := io.ReadAll(lease.Value())
+		assert.NoError(t, err)
+		assert.Equal(t, "payload", string(content))
+	}()
+
+	wg.Wait()
+
+	lease.Release() // idempotent
+
+	snapshot := stats.Snapshot()
+	require.EqualValues(t, 1, snapshot.Gets)
+	require.EqualValues(t, 1, snapshot.Puts)
+}
+
+func TestAutoRelease(t *testing.T) {
+	t.Parallel()
+
+	var stats monadic.Stats
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithStats[[]byte, *bytes.Reader](&stats))
+
+	reader := pool.Get([]byte(`payload`))
+
+	closer := monadic.AutoRelease(pool, reader)
+	assert.Same(t, reader, closer.Value())
+
+	require.NoError(t, closer.Close())
+	require.NoError(t, closer.Close()) // idempotent
+
+	assert.Equal(t, uint64(1), stats.Snapshot().Puts, "must put back exactly once")
+	assert.Zero(t, reader.Len(), "must reset on put")
+}
@@ -0,0 +1,250 @@
+package monadic
+
+import (
+	"reflect"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Option is a functional option to customize a monadic [Pool].
+// It is parameterized on the same generic types S and T of the [Pool].
+type Option[S, T any] func(*options[S, T])
+
+type options[S, T any] struct {
+	onPutResetter func(object T)
+	putState      S
+	noResetOnPut  bool
+	noRetryOnGet  bool
+	transform     func(state S) S
+	stats         *Stats
+	onGetCallback func(object T, err error)
+	onPutCallback func(object T, err error)
+	onDiscard     func(object T)
+	ctorLimiter   xpool.Limiter
+	maxInFlight   int
+	getValidator  func(object T) bool
+	generation    *xpool.Generation
+	hotTierSize   int
+	disabled      bool
+	chaosRate     float64
+}
+
+func buildOptions[S, T any](opts []Option[S, T]) *options[S, T] {
+	o := &options[S, T]{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithPutResetter replaces the resetter called before put the object back to the pool.
+// By default, the pool will reset the object using the zero value of S,
+// this option allows a different and cheaper cleanup, like close some underlying resource.
+// Be careful, the put resetter must be thread safe.
+// It is ignored if [WithNoResetOnPut] is also used.
+// Will panic if onPutResetter is nil.
+func WithPutResetter[S, T any](onPutResetter func(object T)) Option[S, T] {
+	if onPutResetter == nil {
+		panic("callback 'onPutResetter' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.onPutResetter = onPutResetter
+	}
+}
+
+// WithPutState sets the state used by the resetter before put the object back to the pool,
+// instead the zero value of S. Useful when the zero value is not a valid "neutral" state.
+// It is ignored if [WithPutResetter] or [WithNoResetOnPut] are also used.
+func WithPutState[S, T any](putState S) Option[S, T] {
+	return func(o *options[S, T]) {
+		o.putState = putState
+	}
+}
+
+// WithNoResetOnPut disables the resetter before put the object back to the pool.
+// Useful when Get always fully re-binds the object, like [bytes.Reader],
+// and the reset on Put is just wasted work.
+// Be careful, the object will hold the last state while it is on the pool.
+func WithNoResetOnPut[S, T any]() Option[S, T] {
+	return func(o *options[S, T]) {
+		o.noResetOnPut = true
+	}
+}
+
+// WithNoRetryOnGet disables the retry with a fresh object when the resetter fails on Get.
+// Useful when the failure depends only on the state, like a decompressor reading an invalid header,
+// since the state may be consumed by the first attempt.
+// Get will return the object as it is, while GetContext will discard it and return the error.
+func WithNoRetryOnGet[S, T any]() Option[S, T] {
+	return func(o *options[S, T]) {
+		o.noRetryOnGet = true
+	}
+}
+
+// WithStateTransform sets a function to normalize, validate or clone the state on Get,
+// before it is passed to the resetter, like a defensive copy of a []byte or clamping a size.
+// It is called once per Get, even if the resetter is retried with a fresh object.
+// Will panic if transform is nil.
+func WithStateTransform[S, T any](transform func(state S) S) Option[S, T] {
+	if transform == nil {
+		panic("callback 'transform' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.transform = transform
+	}
+}
+
+// WithStats enables the counters of the pool, updating the given [Stats].
+// The same [Stats] can be shared by several pools to aggregate the counters.
+// Will panic if stats is nil.
+func WithStats[S, T any](stats *Stats) Option[S, T] {
+	if stats == nil {
+		panic("argument 'stats' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.stats = stats
+	}
+}
+
+// WithOnGetResetCallback sets a callback to be called after each reset on Get,
+// with the error returned by the resetter, if any.
+// Useful for log, trace and metrics. Be careful, the callback must be thread safe.
+// Will panic if callback is nil.
+func WithOnGetResetCallback[S, T any](onGetReset func(object T, err error)) Option[S, T] {
+	if onGetReset == nil {
+		panic("callback 'onGetReset' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.onGetCallback = onGetReset
+	}
+}
+
+// WithOnPutResetCallback sets a callback to be called after each reset on Put,
+// with the error returned by the resetter, if any. It is not called if [WithNoResetOnPut] is used.
+// Useful for log, trace and metrics. Be careful, the callback must be thread safe.
+// Will panic if callback is nil.
+func WithOnPutResetCallback[S, T any](onPutReset func(object T, err error)) Option[S, T] {
+	if onPutReset == nil {
+		panic("callback 'onPutReset' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.onPutCallback = onPutReset
+	}
+}
+
+// WithOnDiscard sets a callback to be called when the pool discards an object instead reuse it,
+// for instance when the resetter fails. Useful to release resources, like call Close.
+// Objects dropped by the underlying [sync.Pool] during garbage collection are not observed.
+// Be careful, the callback must be thread safe.
+// Will panic if onDiscard is nil.
+func WithOnDiscard[S, T any](onDiscard func(object T)) Option[S, T] {
+	if onDiscard == nil {
+		panic("callback 'onDiscard' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.onDiscard = onDiscard
+	}
+}
+
+// WithCtorLimiter throttles the calls to the constructor when the pool is empty,
+// including the fresh object created when the resetter fails on Get.
+// Get waits for the limiter without a deadline, while GetContext respects the context cancellation
+// and returns the error. See [xpool.WithCtorLimiter].
+// Will panic if limiter is nil.
+func WithCtorLimiter[S, T any](limiter xpool.Limiter) Option[S, T] {
+	if limiter == nil {
+		panic("argument 'limiter' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.ctorLimiter = limiter
+	}
+}
+
+// WithMaxInFlight bounds the number of objects checked out from the pool at the same time,
+// independent of how many objects the pool retains. Get blocks until some object is put back,
+// while GetContext respects the context cancellation and returns the error.
+// Be careful, each object must be put back to the pool exactly once.
+// Will panic if maxInFlight is not positive.
+func WithMaxInFlight[S, T any](maxInFlight int) Option[S, T] {
+	if maxInFlight <= 0 {
+		panic("argument 'maxInFlight' must be positive")
+	}
+
+	return func(o *options[S, T]) {
+		o.maxInFlight = maxInFlight
+	}
+}
+
+// WithGetValidator sets a health check to be called on each object fetched from the pool, before the resetter.
+// If the object fails the check, it is discarded (see [WithOnDiscard]) and another one is fetched or created.
+// Objects created by the constructor are not checked. See [xpool.WithGetValidator].
+// Will panic if validator is nil.
+func WithGetValidator[S, T any](validator func(object T) bool) Option[S, T] {
+	if validator == nil {
+		panic("callback 'validator' must not be nil")
+	}
+
+	return func(o *options[S, T]) {
+		o.getValidator = validator
+	}
+}
+
+// WithGeneration allows invalidate all objects of the pool via [xpool.Generation.Invalidate],
+// for instance after a configuration change. See [xpool.WithGeneration].
+// T must be a pointer type, since the pool tracks the checked out objects by their identity.
+// Will panic if generation is nil, or if T is not a pointer type.
+func WithGeneration[S, T any](generation *xpool.Generation) Option[S, T] {
+	if generation == nil {
+		panic("argument 'generation' must not be nil")
+	}
+
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() != reflect.Ptr {
+		panic("type parameter 'T' must be a pointer type")
+	}
+
+	return func(o *options[S, T]) {
+		o.generation = generation
+	}
+}
+
+// WithHotTier keeps up to size of the most recently used objects on a small array,
+// that survives the garbage collection, before the underlying [sync.Pool]. See [xpool.WithHotTier].
+// Will panic if size is not positive.
+func WithHotTier[S, T any](size int) Option[S, T] {
+	if size <= 0 {
+		panic("argument 'size' must be positive")
+	}
+
+	return func(o *options[S, T]) {
+		o.hotTierSize = size
+	}
+}
+
+// WithDisabled disables the pooling: Get always calls the constructor, and Put drops the object,
+// after the resetter. See [xpool.WithDisabled], and the environment variable [xpool.DisableEnv].
+func WithDisabled[S, T any](disabled bool) Option[S, T] {
+	return func(o *options[S, T]) {
+		o.disabled = disabled
+	}
+}
+
+// WithChaos randomly drops a fraction of the objects on Put, and randomly forces a call to the constructor on Get.
+// See [xpool.WithChaos]. Will panic if rate is not between 0 and 1.
+func WithChaos[S, T any](rate float64) Option[S, T] {
+	if rate < 0 || rate > 1 {
+		panic("argument 'rate' must be between 0 and 1")
+	}
+
+	return func(o *options[S, T]) {
+		o.chaosRate = rate
+	}
+}
@@ -0,0 +1,449 @@
+package monadic_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/monadic"
+)
+
+func TestWithPutResetter(t *testing.T) {
+	t.Parallel()
+
+	var putResets int
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithPutResetter[[]byte](func(r *bytes.Reader) {
+		putResets++
+
+		r.Reset(nil)
+	}))
+
+	reader := pool.Get([]byte(`payload`))
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(content))
+
+	pool.Put(reader)
+
+	assert.Equal(t, 1, putResets)
+	assert.Zero(t, reader.Size())
+}
+
+func TestWithPutResetterNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithPutResetter[[]byte, *bytes.Reader](nil)
+	}, "must panic")
+}
+
+func TestWithPutState(t *testing.T) {
+	t.Parallel()
+
+	parked := []byte(`parked`)
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithPutState[[]byte, *bytes.Reader](parked))
+
+	reader := pool.Get([]byte(`payload`))
+	pool.Put(reader)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "parked", string(content))
+}
+
+func TestWithPutStateAndPutResetter(t *testing.T) {
+	t.Parallel()
+
+	pool := monadic.NewWithCustomResetter(func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, func(r *bytes.Reader, b []byte) {
+		r.Reset(b)
+	},
+		monadic.WithPutState[[]byte, *bytes.Reader]([]byte(`parked`)),
+		monadic.WithPutResetter[[]byte](func(r *bytes.Reader) {
+			r.Reset(nil)
+		}),
+	)
+
+	reader := pool.Get([]byte(`payload`))
+	pool.Put(reader)
+
+	assert.Zero(t, reader.Size(), "put resetter must take precedence")
+}
+
+func TestWithNoResetOnPut(t *testing.T) {
+	t.Parallel()
+
+	var resets int
+
+	pool := monadic.NewWithCustomResetter(func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, func(r *bytes.Reader, b []byte) {
+		resets++
+
+		r.Reset(b)
+	}, monadic.WithNoResetOnPut[[]byte, *bytes.Reader]())
+
+	reader := pool.Get([]byte(`payload`))
+	pool.Put(reader)
+
+	assert.Equal(t, 1, resets, "must reset only on Get")
+	assert.EqualValues(t, len("payload"), reader.Size())
+}
+
+func TestWithResetCallbacks(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, (*fallibleReader).Reset,
+		monadic.WithOnGetResetCallback[string](func(r *fallibleReader, err error) {
+			events = append(events, fmt.Sprintf("get %q %v", r.state, err))
+		}),
+		monadic.WithOnPutResetCallback[string](func(r *fallibleReader, err error) {
+			events = append(events, fmt.Sprintf("put %q %v", r.state, err))
+		}),
+	)
+
+	reader := pool.Get("payload")
+	pool.Put(reader)
+
+	reader.broken = true
+	pool.Put(reader)
+
+	assert.Equal(t, []string{
+		`get "payload" <nil>`,
+		`put "" <nil>`,
+		`put "" broken reader`,
+	}, events)
+}
+
+func TestWithResetCallbacksNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithOnGetResetCallback[string, *fallibleReader](nil)
+	}, "must panic")
+
+	assert.Panics(t, func() {
+		monadic.WithOnPutResetCallback[string, *fallibleReader](nil)
+	}, "must panic")
+}
+
+func TestWithOnDiscard(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*fallibleReader
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, (*fallibleReader).Reset,
+		monadic.WithNoResetOnPut[string, *fallibleReader](),
+		monadic.WithOnDiscard[string](func(r *fallibleReader) {
+			discarded = append(discarded, r)
+		}),
+		monadic.WithHotTier[string, *fallibleReader](1), // the sync.Pool may drop the broken reader
+	)
+
+	broken := &fallibleReader{broken: true}
+	pool.Put(broken)
+
+	reader := pool.Get("payload")
+	defer pool.Put(reader)
+
+	assert.Equal(t, []*fallibleReader{broken}, discarded)
+}
+
+func TestWithOnDiscardNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithOnDiscard[string, *fallibleReader](nil)
+	}, "must panic")
+}
+
+func TestWithNoRetryOnGet(t *testing.T) {
+	t.Parallel()
+
+	var ctorCalls int
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		ctorCalls++
+
+		return &fallibleReader{}
+	}, func(r *fallibleReader, state string) error {
+		if state == "invalid" {
+			return errors.New("invalid state")
+		}
+
+		return r.Reset(state)
+	}, monadic.WithNoRetryOnGet[string, *fallibleReader]())
+
+	reader := pool.Get("invalid")
+	assert.NotNil(t, reader)
+	assert.Equal(t, 1, ctorCalls, "must not retry")
+
+	_, err := pool.GetContext(context.Background(), "invalid")
+	require.EqualError(t, err, "invalid state")
+	assert.Equal(t, 2, ctorCalls, "must not retry")
+}
+
+type tokenLimiter chan struct{}
+
+func (l tokenLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestWithCtorLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := make(tokenLimiter, 1)
+	limiter <- struct{}{}
+
+	var ctorCalls int
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		ctorCalls++
+
+		return &fallibleReader{}
+	}, func(r *fallibleReader, state string) error {
+		return r.Reset(state)
+	}, monadic.WithCtorLimiter[string, *fallibleReader](limiter),
+		monadic.WithHotTier[string, *fallibleReader](1), // the sync.Pool may drop the idle object
+	)
+
+	reader, err := pool.GetContext(context.Background(), "foo")
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = pool.GetContext(ctx, "bar")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, ctorCalls, "must not call the constructor without a token")
+
+	// the fresh object created on retry must also wait for the limiter.
+	pool.Put(reader)
+	reader.broken = true
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = pool.GetContext(ctx, "baz")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, ctorCalls, "must not call the constructor without a token")
+}
+
+func TestWithCtorLimiterNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithCtorLimiter[string, *fallibleReader](nil)
+	}, "must panic")
+}
+
+func TestWithMaxInFlight(t *testing.T) {
+	t.Parallel()
+
+	pool := monadic.NewE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, monadic.WithMaxInFlight[string, *fallibleReader](1))
+
+	reader := pool.Get("foo")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := pool.GetContext(ctx, "bar")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	reader.broken = true
+	pool.Put(reader) // discarded, must release the slot anyway
+
+	err = pool.With("baz", func(r *fallibleReader) error {
+		assert.Equal(t, "baz", r.state)
+
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestWithMaxInFlightInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithMaxInFlight[string, *fallibleReader](-1)
+	}, "must panic")
+}
+
+func TestWithGetValidator(t *testing.T) {
+	t.Parallel()
+
+	var discards int
+
+	pool := monadic.NewE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, monadic.WithGetValidator[string](func(r *fallibleReader) bool {
+		return r.state != "stale"
+	}), monadic.WithOnDiscard[string](func(*fallibleReader) {
+		discards++
+	}), monadic.WithNoResetOnPut[string, *fallibleReader](),
+		monadic.WithHotTier[string, *fallibleReader](1), // the sync.Pool may drop the idle object
+	)
+
+	reader := pool.Get("stale")
+	pool.Put(reader)
+
+	other := pool.Get("fresh")
+	assert.NotSame(t, reader, other)
+	assert.Equal(t, "fresh", other.state)
+	assert.Equal(t, 1, discards)
+}
+
+func TestWithHotTier(t *testing.T) {
+	t.Parallel()
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithHotTier[[]byte, *bytes.Reader](1))
+
+	reader := pool.Get([]byte("foo"))
+	pool.Put(reader)
+
+	assert.Same(t, reader, pool.Get([]byte("bar")), "must survive on the hot tier")
+
+	assert.PanicsWithValue(t, "argument 'size' must be positive", func() {
+		monadic.WithHotTier[[]byte, *bytes.Reader](0)
+	})
+}
+
+func TestWithGetValidatorNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithGetValidator[string, *fallibleReader](nil)
+	}, "must panic")
+}
+
+func TestWithGeneration(t *testing.T) {
+	t.Parallel()
+
+	var (
+		generation xpool.Generation
+		discards   int
+	)
+
+	pool := monadic.NewE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, monadic.WithGeneration[string, *fallibleReader](&generation), monadic.WithOnDiscard[string](func(*fallibleReader) {
+		discards++
+	}))
+
+	reader := pool.Get("foo")
+
+	generation.Invalidate()
+
+	pool.Put(reader)
+	assert.Equal(t, 1, discards, "must discard the object of the old generation")
+}
+
+func TestWithGenerationNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithGeneration[string, *fallibleReader](nil)
+	}, "must panic")
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		monadic.WithGeneration[string, []byte](new(xpool.Generation))
+	}, "must panic if T is not comparable")
+}
+
+func TestWithDisabled(t *testing.T) {
+	t.Parallel()
+
+	pool := monadic.NewE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, monadic.WithDisabled[string, *fallibleReader](true))
+
+	reader := pool.Get("foo")
+	pool.Put(reader)
+
+	other := pool.Get("bar")
+	assert.NotSame(t, reader, other, "must always create")
+	assert.Equal(t, "bar", other.state)
+}
+
+func TestWithChaos(t *testing.T) {
+	t.Parallel()
+
+	var stats monadic.Stats
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithChaos[[]byte, *bytes.Reader](1), monadic.WithStats[[]byte, *bytes.Reader](&stats))
+
+	for i := 0; i < 10; i++ {
+		pool.Put(pool.Get([]byte("payload")))
+	}
+
+	assert.Equal(t, uint64(10), stats.Snapshot().News, "must always miss")
+
+	assert.Panics(t, func() {
+		monadic.WithChaos[[]byte, *bytes.Reader](-1)
+	}, "must panic")
+}
+
+func TestWithStateTransform(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithStateTransform[[]byte, *bytes.Reader](func(state []byte) []byte {
+		calls++
+
+		return append([]byte(nil), state...) // defensive copy
+	}))
+
+	payload := []byte("payload")
+
+	reader := pool.Get(payload)
+	defer pool.Put(reader)
+
+	payload[0] = 'P'
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	assert.Equal(t, "payload", string(content), "must read the copy")
+	assert.Equal(t, 1, calls)
+
+	assert.Panics(t, func() {
+		monadic.WithStateTransform[[]byte, *bytes.Reader](nil)
+	}, "must panic")
+}
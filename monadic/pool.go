@@ -29,7 +29,11 @@
 package monadic
 
 import (
+	"context"
+	"io"
+
 	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/internal/semaphore"
 )
 
 // Pool monadic is a type-safe object pool interface.
@@ -41,8 +45,20 @@ type Pool[S, T any] interface {
 	// The state S will be used in the resetter.
 	Get(state S) T
 
+	// GetContext fetch one item from object pool, like Get, but it respects the context cancellation
+	// while waiting for an object. It returns an error if the context is done or if the resetter fails.
+	GetContext(ctx context.Context, state S) (T, error)
+
+	// With fetch one item from object pool with a given state, like Get, and call fn with it.
+	// The object is always put back to the pool, even if fn panics.
+	With(state S, fn func(object T) error) error
+
+	// Lease fetch one item from object pool with a given state, like Get, and returns a handle
+	// that put the object back to the pool on Release.
+	Lease(state S) *Lease[T]
+
 	// Put return the object to the pull.
-	// A zero value of T will be used in the resetter.
+	// By default, a zero value of S will be used in the resetter.
 	Put(object T)
 }
 
@@ -51,77 +67,347 @@ type Resetter[S any] interface {
 	Reset(state S)
 }
 
+// ResetCloser monadic interface, for objects that must be closed when discarded by the pool.
+type ResetCloser[S any] interface {
+	Resetter[S]
+	io.Closer
+}
+
+// ResetterE monadic interface, like [Resetter] but the Reset may fail.
+type ResetterE[S any] interface {
+	Reset(state S) error
+}
+
 // New is the constructor of an [Pool] for a given set of generic types S and T.
 // Receives the constructor of the type T.
 // It sets a trivial resetter, T must be a [Resetter]
 // will call Reset(state S) before return the object on Get(state S)
 // will call Reset(zero value of S) before push back to the pool.
+// The behavior can be customized via [Option].
 func New[S any, T Resetter[S]](
 	ctor func() T,
+	opts ...Option[S, T],
 ) Pool[S, T] {
-	return newWithResetters[S, T](
+	return newWithResetter[S, T](
 		ctor,
-		func(object T, state S) {
+		func(object T, state S) error {
 			object.Reset(state)
+
+			return nil
 		},
-		func(object T) {
-			var zero S
+		opts,
+	)
+}
 
-			object.Reset(zero)
+// NewE is the constructor of an [Pool] for a given set of generic types S and T.
+// Similar to [New], but T must be a [ResetterE], with the same discard semantics
+// of [NewWithCustomResetterE] when Reset fails.
+// The behavior can be customized via [Option].
+func NewE[S any, T ResetterE[S]](
+	ctor func() T,
+	opts ...Option[S, T],
+) Pool[S, T] {
+	return newWithResetter[S, T](
+		ctor,
+		func(object T, state S) error {
+			return object.Reset(state)
 		},
+		opts,
 	)
 }
 
+// NewWithResetCloser is the constructor of an [Pool] for a given set of generic types S and T.
+// Similar to [New], but T must be a [ResetCloser], when the pool discards the object
+// we will call Close(), before the callback set via [WithOnDiscard], if any.
+// The behavior can be customized via [Option].
+func NewWithResetCloser[S any, T ResetCloser[S]](
+	ctor func() T,
+	opts ...Option[S, T],
+) Pool[S, T] {
+	// the close is applied last, so it is chained with the callback set via [WithOnDiscard], if any.
+	return New(ctor, append(opts[:len(opts):len(opts)], closeOnDiscard[S, T]())...)
+}
+
+// closeOnDiscard calls Close on the discarded objects, before the callback set via [WithOnDiscard], if any.
+func closeOnDiscard[S any, T io.Closer]() Option[S, T] {
+	return func(o *options[S, T]) {
+		onDiscard := o.onDiscard
+
+		o.onDiscard = func(object T) {
+			_ = object.Close()
+
+			if onDiscard != nil {
+				onDiscard(object)
+			}
+		}
+	}
+}
+
+// NewWithPutState is an alternative constructor of an [Pool] for a given set of generic types S and T.
+// Similar to [New], but it will call Reset(putState) before push back to the pool,
+// instead use the zero value of S. See [WithPutState].
+func NewWithPutState[S any, T Resetter[S]](
+	ctor func() T,
+	putState S,
+	opts ...Option[S, T],
+) Pool[S, T] {
+	return New(ctor, append([]Option[S, T]{WithPutState[S, T](putState)}, opts...)...)
+}
+
 // NewWithCustomResetter is the constructor of an [Pool] for a given set of generic types S and T.
 // Receives the constructor of the type T as a callback.
 // We can specify a special resetter, to be called with a zero value of S before
 // return the object from the pool.
 // Be careful, the custom resetter must be thread safe.
+// The behavior can be customized via [Option].
 func NewWithCustomResetter[S, T any](
 	ctor func() T,
 	customResetter func(object T, state S),
+	opts ...Option[S, T],
 ) Pool[S, T] {
-	return newWithResetters[S, T](
+	return newWithResetter[S, T](
 		ctor,
-		customResetter,
-		wrapResetToZeroValue(customResetter),
+		func(object T, state S) error {
+			customResetter(object, state)
+
+			return nil
+		},
+		opts,
 	)
 }
 
-func wrapResetToZeroValue[S, T any](customResetter func(object T, state S)) func(object T) {
-	return func(object T) {
-		var zero S
-
-		customResetter(object, zero)
-	}
+// NewWithCustomResetterE is the constructor of an [Pool] for a given set of generic types S and T.
+// Similar to [NewWithCustomResetter], but the custom resetter may return an error.
+// If the resetter fails on Get, the object is discarded and a fresh one is created and resetted instead,
+// unless [WithNoRetryOnGet] is used.
+// If the resetter fails on Put, the object is discarded instead put it back to the pool.
+// Be careful, the custom resetter must be thread safe.
+// The behavior can be customized via [Option].
+func NewWithCustomResetterE[S, T any](
+	ctor func() T,
+	customResetter func(object T, state S) error,
+	opts ...Option[S, T],
+) Pool[S, T] {
+	return newWithResetter[S, T](ctor, customResetter, opts)
 }
 
-func newWithResetters[S, T any](
+func newWithResetter[S, T any](
 	ctor func() T,
-	onGetResetter func(object T, state S),
-	onPutResetter func(object T),
+	customResetter func(object T, state S) error,
+	opts []Option[S, T],
 ) Pool[S, T] {
-	pool := xpool.NewWithCustomResetter[T](ctor, onPutResetter)
+	o := buildOptions(opts)
 
-	return &resettableMonadicPool[S, T]{
-		pool:          pool,
-		onGetResetter: onGetResetter,
+	p := &resettableMonadicPool[S, T]{
+		ctor:          ctor,
+		resetter:      customResetter,
+		putResetter:   o.onPutResetter,
+		putState:      o.putState,
+		noResetOnPut:  o.noResetOnPut,
+		onGetCallback: o.onGetCallback,
+		onPutCallback: o.onPutCallback,
+		ctorLimiter:   o.ctorLimiter,
+		noRetryOnGet:  o.noRetryOnGet,
+		transform:     o.transform,
+		inFlight:      semaphore.New(o.maxInFlight),
+		stats:         o.stats,
 	}
+
+	var poolOpts []xpool.Option[T]
+	if o.ctorLimiter != nil {
+		poolOpts = append(poolOpts, xpool.WithCtorLimiter[T](o.ctorLimiter))
+	}
+
+	if o.getValidator != nil {
+		poolOpts = append(poolOpts, xpool.WithGetValidator(o.getValidator))
+	}
+
+	if o.hotTierSize > 0 {
+		poolOpts = append(poolOpts, xpool.WithHotTier[T](o.hotTierSize))
+	}
+
+	if o.disabled {
+		poolOpts = append(poolOpts, xpool.WithDisabled[T](true))
+	}
+
+	if o.chaosRate > 0 {
+		poolOpts = append(poolOpts, xpool.WithChaos[T](o.chaosRate))
+	}
+
+	if o.generation != nil {
+		poolOpts = append(poolOpts, xpool.WithGeneration[T](o.generation))
+	}
+
+	if o.onDiscard != nil {
+		poolOpts = append(poolOpts, xpool.WithOnDiscard(o.onDiscard))
+	}
+
+	p.pool = xpool.New(p.newObject, poolOpts...)
+
+	return p
 }
 
+// resettableMonadicPool holds the configuration once, instead nesting closures per option,
+// so the Get and Put hot paths only check a few fields.
 type resettableMonadicPool[S, T any] struct {
 	pool          xpool.Pool[T]
-	onGetResetter func(object T, state S)
+	ctor          func() T
+	resetter      func(object T, state S) error
+	putResetter   func(object T)
+	putState      S
+	noResetOnPut  bool
+	onGetCallback func(object T, err error)
+	onPutCallback func(object T, err error)
+	ctorLimiter   xpool.Limiter
+	noRetryOnGet  bool
+	transform     func(state S) S
+	inFlight      semaphore.Semaphore
+	stats         *Stats
 }
 
 func (p *resettableMonadicPool[S, T]) Get(state S) T {
-	object := p.pool.Get()
+	// without a deadline the semaphore will wait forever.
+	_ = p.inFlight.Acquire(context.Background())
+
+	p.stats.incGets()
 
-	p.onGetResetter(object, state)
+	object, _ := p.reset(context.Background(), p.pool.Get(), state)
 
 	return object
 }
 
+func (p *resettableMonadicPool[S, T]) GetContext(ctx context.Context, state S) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if err := p.inFlight.Acquire(ctx); err != nil {
+		return zero, err
+	}
+
+	p.stats.incGets()
+
+	object, err := xpool.GetContext(ctx, p.pool)
+	if err != nil {
+		p.inFlight.Release()
+
+		return zero, err
+	}
+
+	object, err = p.reset(ctx, object, state)
+	if err != nil {
+		p.discard(object)
+		p.inFlight.Release()
+
+		return zero, err
+	}
+
+	return object, nil
+}
+
+func (p *resettableMonadicPool[S, T]) With(state S, fn func(object T) error) error {
+	object := p.Get(state)
+	defer p.Put(object)
+
+	return fn(object)
+}
+
+func (p *resettableMonadicPool[S, T]) Lease(state S) *Lease[T] {
+	return &Lease[T]{
+		object: p.Get(state),
+		put:    p.Put,
+	}
+}
+
+func (p *resettableMonadicPool[S, T]) reset(ctx context.Context, object T, state S) (T, error) {
+	if p.transform != nil {
+		state = p.transform(state)
+	}
+
+	if err := p.resetOnGet(object, state); err != nil {
+		p.stats.incResetFailures()
+
+		if p.noRetryOnGet {
+			return object, err
+		}
+
+		if p.ctorLimiter != nil {
+			// a context that can never be canceled should not fail, like the plain Get.
+			if err = p.ctorLimiter.Wait(ctx); err != nil && ctx.Done() != nil {
+				return object, err
+			}
+		}
+
+		// discard the object, the fresh one may also fail depending on the state.
+		p.discard(object)
+
+		object = p.newObject()
+
+		if err = p.resetOnGet(object, state); err != nil {
+			p.stats.incResetFailures()
+		}
+
+		return object, err
+	}
+
+	return object, nil
+}
+
 func (p *resettableMonadicPool[_, T]) Put(object T) {
-	p.pool.Put(object) // will call Reset with zero value
+	defer p.inFlight.Release()
+
+	p.stats.incPuts()
+
+	if err := p.resetOnPut(object); err != nil {
+		p.stats.incResetFailures()
+
+		p.discard(object)
+
+		return
+	}
+
+	p.pool.Put(object)
+}
+
+func (p *resettableMonadicPool[_, T]) newObject() T {
+	p.stats.incNews()
+
+	return p.ctor()
+}
+
+func (p *resettableMonadicPool[S, T]) resetOnGet(object T, state S) error {
+	err := p.resetter(object, state)
+
+	if p.onGetCallback != nil {
+		p.onGetCallback(object, err)
+	}
+
+	return err
+}
+
+func (p *resettableMonadicPool[_, T]) resetOnPut(object T) error {
+	if p.noResetOnPut {
+		return nil
+	}
+
+	var err error
+
+	if p.putResetter != nil {
+		p.putResetter(object)
+	} else {
+		err = p.resetter(object, p.putState)
+	}
+
+	if p.onPutCallback != nil {
+		p.onPutCallback(object, err)
+	}
+
+	return err
+}
+
+// discard drops the object via the underlying pool, that calls the callback set via [WithOnDiscard].
+func (p *resettableMonadicPool[_, T]) discard(object T) {
+	xpool.Discard(p.pool, object)
 }
@@ -3,6 +3,8 @@ package monadic_test
 import (
 	"bytes"
 	"compress/flate"
+	"context"
+	"errors"
 	"io"
 	"log"
 	"os"
@@ -10,8 +12,10 @@ import (
 	"testing"
 	"testing/quick"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/peczenyj/xpool"
 	"github.com/peczenyj/xpool/monadic"
 )
 
@@ -110,3 +114,252 @@ func ExampleNewWithCustomResetter() {
 	// Output:
 	// hello, world!
 }
+
+type fallibleReader struct {
+	broken bool
+	state  string
+}
+
+func (r *fallibleReader) Reset(state string) error {
+	if r.broken {
+		return errors.New("broken reader")
+	}
+
+	r.state = state
+
+	return nil
+}
+
+func TestNewWithCustomResetterE(t *testing.T) {
+	t.Parallel()
+
+	var ctorCalls int
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		ctorCalls++
+
+		return &fallibleReader{}
+	}, (*fallibleReader).Reset)
+
+	reader := pool.Get("first")
+	require.Equal(t, "first", reader.state)
+	require.Equal(t, 1, ctorCalls)
+
+	reader.broken = true
+
+	t.Run("discard on put failure", func(t *testing.T) {
+		pool.Put(reader) // must be discarded
+
+		other := pool.Get("second")
+		defer pool.Put(other)
+
+		assert.NotSame(t, reader, other)
+		assert.Equal(t, "second", other.state)
+	})
+}
+
+func TestNewWithCustomResetterEDiscardOnGet(t *testing.T) {
+	t.Parallel()
+
+	broken := &fallibleReader{}
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, (*fallibleReader).Reset, monadic.WithNoResetOnPut[string, *fallibleReader]())
+
+	pool.Put(broken)
+
+	broken.broken = true
+
+	reader := pool.Get("payload")
+	defer pool.Put(reader)
+
+	assert.NotSame(t, broken, reader)
+	assert.Equal(t, "payload", reader.state)
+}
+
+func TestGetContext(t *testing.T) {
+	t.Parallel()
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, func(r *fallibleReader, state string) error {
+		if state == "invalid" {
+			return errors.New("invalid state")
+		}
+
+		return r.Reset(state)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+
+		reader, err := pool.GetContext(context.Background(), "payload")
+		require.NoError(t, err)
+
+		defer pool.Put(reader)
+
+		assert.Equal(t, "payload", reader.state)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		reader, err := pool.GetContext(ctx, "payload")
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, reader)
+	})
+
+	t.Run("resetter fails", func(t *testing.T) {
+		t.Parallel()
+
+		reader, err := pool.GetContext(context.Background(), "invalid")
+		require.EqualError(t, err, "invalid state")
+		assert.Nil(t, reader)
+	})
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	var putResets int
+
+	pool := monadic.New[[]byte](func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, monadic.WithPutResetter[[]byte](func(r *bytes.Reader) {
+		putResets++
+
+		r.Reset(nil)
+	}))
+
+	var content []byte
+
+	err := pool.With([]byte(`payload`), func(r *bytes.Reader) (err error) {
+		content, err = io.ReadAll(r)
+
+		return err
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "payload", string(content))
+	assert.Equal(t, 1, putResets)
+
+	errExpected := errors.New("ops")
+
+	err = pool.With([]byte(`payload`), func(*bytes.Reader) error {
+		return errExpected
+	})
+	require.ErrorIs(t, err, errExpected)
+	assert.Equal(t, 2, putResets)
+
+	assert.Panics(t, func() {
+		_ = pool.With([]byte(`payload`), func(*bytes.Reader) error {
+			panic("ops")
+		})
+	})
+	assert.Equal(t, 3, putResets, "must put the object back to the pool on panic")
+}
+
+func TestNewWithPutState(t *testing.T) {
+	t.Parallel()
+
+	pool := monadic.NewWithPutState(func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, []byte(`parked`))
+
+	reader := pool.Get([]byte(`payload`))
+	pool.Put(reader)
+
+	content, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "parked", string(content))
+}
+
+func TestNewE(t *testing.T) {
+	t.Parallel()
+
+	var stats monadic.Stats
+
+	pool := monadic.NewE[string](func() *fallibleReader {
+		return &fallibleReader{}
+	}, monadic.WithStats[string, *fallibleReader](&stats))
+
+	reader := pool.Get("payload")
+	assert.Equal(t, "payload", reader.state)
+
+	reader.broken = true
+
+	pool.Put(reader) // must be discarded
+
+	assert.EqualValues(t, 1, stats.Snapshot().ResetFailures)
+}
+
+func BenchmarkGetPut(b *testing.B) {
+	newReader := func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}
+
+	payload := []byte(`payload`)
+
+	for name, pool := range map[string]monadic.Pool[[]byte, *bytes.Reader]{
+		"New": monadic.New[[]byte](newReader),
+		"WithStats": monadic.New[[]byte](newReader,
+			monadic.WithStats[[]byte, *bytes.Reader](new(monadic.Stats))),
+		"WithCallbacks": monadic.New[[]byte](newReader,
+			monadic.WithOnGetResetCallback[[]byte](func(*bytes.Reader, error) {}),
+			monadic.WithOnPutResetCallback[[]byte](func(*bytes.Reader, error) {})),
+	} {
+		pool := pool
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				pool.Put(pool.Get(payload))
+			}
+		})
+	}
+}
+
+type stateCloser struct {
+	state  string
+	closed bool
+}
+
+func (s *stateCloser) Reset(state string) { s.state = state }
+
+func (s *stateCloser) Close() error {
+	s.closed = true
+
+	return nil
+}
+
+func TestNewWithResetCloserOnDiscard(t *testing.T) {
+	t.Parallel()
+
+	var (
+		generation xpool.Generation
+		discarded  []*stateCloser
+	)
+
+	pool := monadic.NewWithResetCloser[string](func() *stateCloser {
+		return new(stateCloser)
+	}, monadic.WithGeneration[string, *stateCloser](&generation), monadic.WithOnDiscard[string](func(object *stateCloser) {
+		assert.True(t, object.closed, "must close before the callback")
+
+		discarded = append(discarded, object)
+	}))
+
+	object := pool.Get("state")
+	pool.Put(object)
+	assert.False(t, object.closed, "must not close on a regular put")
+
+	object = pool.Get("state")
+	generation.Invalidate()
+	pool.Put(object) // discarded on put
+
+	assert.True(t, object.closed, "must close on discard")
+	assert.Equal(t, []*stateCloser{object}, discarded)
+}
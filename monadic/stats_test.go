@@ -0,0 +1,43 @@
+package monadic_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool/monadic"
+)
+
+func TestWithStats(t *testing.T) {
+	t.Parallel()
+
+	var stats monadic.Stats
+
+	pool := monadic.NewWithCustomResetterE(func() *fallibleReader {
+		return &fallibleReader{}
+	}, (*fallibleReader).Reset, monadic.WithStats[string, *fallibleReader](&stats))
+
+	reader1 := pool.Get("first")
+	reader2 := pool.Get("second")
+
+	pool.Put(reader1)
+
+	reader2.broken = true
+
+	pool.Put(reader2) // must fail and discard
+
+	assert.Equal(t, monadic.StatsSnapshot{
+		Gets:          2,
+		Puts:          2,
+		News:          2,
+		ResetFailures: 1,
+	}, stats.Snapshot())
+}
+
+func TestWithStatsNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		monadic.WithStats[string, *fallibleReader](nil)
+	}, "must panic")
+}
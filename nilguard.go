@@ -0,0 +1,86 @@
+package xpool
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// NilPolicy is called by the pool when the constructor returns nil, see [WithNilGuard].
+// Receives the constructor and returns the object to be used instead.
+type NilPolicy[T any] func(ctor func() T) T
+
+// NilPanic returns a [NilPolicy] that panics with a clear message, close to the real bug,
+// instead a nil pointer dereference far away from it.
+func NilPanic[T any]() NilPolicy[T] {
+	return func(func() T) T {
+		panic(nilCtorMessage[T]())
+	}
+}
+
+// NilRetry returns a [NilPolicy] that calls the constructor again up to attempts times,
+// and panics like [NilPanic] if all of them return nil.
+// Will panic if attempts is not positive.
+func NilRetry[T any](attempts int) NilPolicy[T] {
+	if attempts <= 0 {
+		panic("argument 'attempts' must be positive")
+	}
+
+	return func(ctor func() T) T {
+		for i := 0; i < attempts; i++ {
+			if object := ctor(); !isNil(object) {
+				return object
+			}
+		}
+
+		panic(nilCtorMessage[T]())
+	}
+}
+
+// NilFallback returns a [NilPolicy] that calls the fallback constructor instead.
+// Will panic if fallback is nil.
+func NilFallback[T any](fallback func() T) NilPolicy[T] {
+	if fallback == nil {
+		panic("callback 'fallback' must not be nil")
+	}
+
+	return func(func() T) T {
+		return fallback()
+	}
+}
+
+func nilCtorMessage[T any]() string {
+	return fmt.Sprintf("xpool: the constructor of %s returned nil", reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// guardNil wraps the constructor, calling the policy when it returns nil.
+func guardNil[T any](ctor func() T, policy NilPolicy[T]) func() T {
+	return func() T {
+		object := ctor()
+		if isNil(object) {
+			return policy(ctor)
+		}
+
+		return object
+	}
+}
+
+// canBeNil tells if T is a pointer, interface or any other type that can be nil.
+func canBeNil[T any]() bool {
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Interface, reflect.Slice:
+		return true
+	default:
+		return false
+	}
+}
+
+func isNil[T any](object T) bool {
+	value := reflect.ValueOf(&object).Elem()
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Interface, reflect.Slice:
+		return value.IsNil()
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package xpool_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithNilGuardPanic(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return nil
+	}, xpool.WithNilGuard(xpool.NilPanic[*bytes.Buffer]()))
+
+	assert.PanicsWithValue(t, "xpool: the constructor of *bytes.Buffer returned nil", func() {
+		pool.Get()
+	})
+}
+
+func TestWithNilGuardRetry(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	pool := xpool.New(func() io.Reader {
+		calls++
+		if calls < 3 {
+			return nil
+		}
+
+		return new(bytes.Buffer)
+	}, xpool.WithNilGuard(xpool.NilRetry[io.Reader](3)))
+
+	assert.NotNil(t, pool.Get())
+	assert.Equal(t, 3, calls)
+
+	failing := xpool.New(func() io.Reader {
+		return nil
+	}, xpool.WithNilGuard(xpool.NilRetry[io.Reader](2)))
+
+	assert.PanicsWithValue(t, "xpool: the constructor of io.Reader returned nil", func() {
+		failing.Get()
+	})
+}
+
+func TestWithNilGuardFallback(t *testing.T) {
+	t.Parallel()
+
+	fallback := new(bytes.Buffer)
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return nil
+	}, xpool.WithNilGuard(xpool.NilFallback(func() *bytes.Buffer {
+		return fallback
+	})))
+
+	assert.Same(t, fallback, pool.Get())
+}
+
+func TestWithNilGuardInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithNilGuard[*bytes.Buffer](nil)
+	}, "must panic if policy is nil")
+
+	assert.Panics(t, func() {
+		xpool.WithNilGuard(xpool.NilPanic[bytes.Buffer]())
+	}, "must panic if T can not be nil")
+
+	assert.Panics(t, func() {
+		xpool.NilRetry[*bytes.Buffer](0)
+	}, "must panic if attempts is not positive")
+
+	assert.Panics(t, func() {
+		xpool.NilFallback[*bytes.Buffer](nil)
+	}, "must panic if fallback is nil")
+}
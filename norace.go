@@ -0,0 +1,6 @@
+//go:build !race
+
+package xpool
+
+// raceEnabled tells if the race detector is enabled, see [WithStrictOwnership].
+const raceEnabled = false
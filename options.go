@@ -0,0 +1,451 @@
+package xpool
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+// Option is a functional option to customize a [Pool].
+// It is parameterized on the same generic type T of the [Pool].
+type Option[T any] func(*options[T])
+
+type options[T any] struct {
+	stats         *Stats
+	onPutCallback func(object T, err error)
+	onDiscard     func(object T)
+	ctorLimiter   Limiter
+	maxInFlight   int
+	maxUses       int
+	getValidator  func(object T) bool
+	generation    *Generation
+	hotTierSize   int
+	evictionStats bool
+	disabled      bool
+	chaosRate     float64
+	recorder      *Recorder
+	keepFields    []string
+	trimmer       Trimmer[T]
+	nilPolicy     NilPolicy[T]
+	ctorRecover   func(recovered any) (T, bool)
+	lostObjects   bool
+	replaceLost   bool
+	holdTimes     *Histogram
+	outstanding   *Outstanding
+	onViolation   func(object T, owner, caller uint64)
+	typeCheck     *typeCheck[T]
+	clock         Clock
+	maxObjectAge  time.Duration
+}
+
+func buildOptions[T any](opts []Option[T]) *options[T] {
+	o := &options[T]{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// getClock returns the clock set via [WithClock], or the [SystemClock].
+func (o *options[T]) getClock() Clock {
+	if o.clock == nil {
+		return SystemClock
+	}
+
+	return o.clock
+}
+
+// isDisabled tells if the pooling is disabled, via option or environment variable.
+func (o *options[T]) isDisabled() bool {
+	return o.disabled || disabledByEnv()
+}
+
+// interceptsMisses tells if some option must intercept the pool misses, or track the objects.
+func (o *options[T]) interceptsMisses() bool {
+	return o.ctorLimiter != nil ||
+		o.maxInFlight > 0 ||
+		o.maxUses > 0 ||
+		o.maxObjectAge > 0 ||
+		o.getValidator != nil ||
+		o.generation != nil ||
+		o.hotTierSize > 0 ||
+		o.evictionStats ||
+		o.chaosRate > 0 ||
+		o.lostObjects
+}
+
+// WithStats enables the counters of the pool, updating the given [Stats].
+// The same [Stats] can be shared by several pools to aggregate the counters.
+// Will panic if stats is nil.
+func WithStats[T any](stats *Stats) Option[T] {
+	if stats == nil {
+		panic("argument 'stats' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.stats = stats
+	}
+}
+
+// WithOnPutResetCallback sets a callback to be called after each reset on Put,
+// with the error returned by the resetter, if any.
+// It is only used by the resettable pools, like [NewWithResetter] and [NewWithCustomResetter].
+// Useful for log, trace and metrics. Be careful, the callback must be thread safe.
+// Will panic if onPutReset is nil.
+func WithOnPutResetCallback[T any](onPutReset func(object T, err error)) Option[T] {
+	if onPutReset == nil {
+		panic("callback 'onPutReset' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.onPutCallback = onPutReset
+	}
+}
+
+// WithOnDiscard sets a callback to be called when the pool discards an object instead reuse it,
+// for instance when the resetter fails. Useful to release resources, like call Close.
+// Objects dropped by the underlying [sync.Pool] during garbage collection are not observed.
+// Be careful, the callback must be thread safe.
+// Will panic if onDiscard is nil.
+func WithOnDiscard[T any](onDiscard func(object T)) Option[T] {
+	if onDiscard == nil {
+		panic("callback 'onDiscard' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.onDiscard = onDiscard
+	}
+}
+
+// Limiter is the interface that throttles the calls to the constructor, see [WithCtorLimiter].
+// A *rate.Limiter from golang.org/x/time/rate implements this interface.
+type Limiter interface {
+	// Wait blocks until the constructor can be called, or returns an error if the context is done.
+	Wait(ctx context.Context) error
+}
+
+// WithCtorLimiter throttles the calls to the constructor when the pool is empty.
+// Get waits for the limiter without a deadline, and if the limiter fails the object is created anyway,
+// while [GetContext] respects the context cancellation and returns the error.
+// Useful to avoid a cold-start stampede of expensive constructors.
+// Will panic if limiter is nil.
+func WithCtorLimiter[T any](limiter Limiter) Option[T] {
+	if limiter == nil {
+		panic("argument 'limiter' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.ctorLimiter = limiter
+	}
+}
+
+// WithMaxInFlight bounds the number of objects checked out from the pool at the same time,
+// independent of how many objects the pool retains. Get blocks until some object is put back,
+// while [GetContext] respects the context cancellation and returns the error.
+// Be careful, each object must be put back to the pool exactly once.
+// Will panic if maxInFlight is not positive.
+func WithMaxInFlight[T any](maxInFlight int) Option[T] {
+	if maxInFlight <= 0 {
+		panic("argument 'maxInFlight' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.maxInFlight = maxInFlight
+	}
+}
+
+// WithMaxUses retires an object after it was fetched from the pool maxUses times:
+// on Put, the object is discarded instead put it back to the pool, see [WithOnDiscard].
+// Useful for objects that accumulate internal fragmentation and are cheaper to rebuild periodically.
+// T must be a pointer type, since the pool tracks the checked out objects by their identity.
+// Will panic if maxUses is not positive, or if T is not a pointer type.
+func WithMaxUses[T any](maxUses int) Option[T] {
+	if maxUses <= 0 {
+		panic("argument 'maxUses' must be positive")
+	}
+
+	requirePointer[T]()
+
+	return func(o *options[T]) {
+		o.maxUses = maxUses
+	}
+}
+
+// WithMaxObjectAge retires the objects older than maxAge, since their construction: they are discarded
+// and replaced by fresh ones when fetched from the pool, or discarded on Put, see [WithOnDiscard].
+// Useful for objects that embed something that expires, like a session token. The age uses the clock set via [WithClock].
+// T must be a pointer type, since the pool tracks the checked out objects by their identity.
+// Will panic if maxAge is not positive, or if T is not a pointer type.
+func WithMaxObjectAge[T any](maxAge time.Duration) Option[T] {
+	if maxAge <= 0 {
+		panic("argument 'maxAge' must be positive")
+	}
+
+	requirePointer[T]()
+
+	return func(o *options[T]) {
+		o.maxObjectAge = maxAge
+	}
+}
+
+// WithGetValidator sets a health check to be called on each object fetched from the pool, before return it.
+// If the object fails the check, it is discarded (see [WithOnDiscard]) and another one is fetched or created.
+// Objects created by the constructor are not checked.
+// Useful for objects that can silently go stale, like prepared statements.
+// Be careful, the validator must be thread safe.
+// Will panic if validator is nil.
+func WithGetValidator[T any](validator func(object T) bool) Option[T] {
+	if validator == nil {
+		panic("callback 'validator' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.getValidator = validator
+	}
+}
+
+// WithGeneration allows invalidate all objects of the pool via [Generation.Invalidate],
+// for instance after a configuration change. The invalidated objects are discarded, see [WithOnDiscard].
+// The same [Generation] can be shared by several pools to invalidate all of them at once.
+// T must be a pointer type, since the pool tracks the checked out objects by their identity.
+// Will panic if generation is nil, or if T is not a pointer type.
+func WithGeneration[T any](generation *Generation) Option[T] {
+	if generation == nil {
+		panic("argument 'generation' must not be nil")
+	}
+
+	requirePointer[T]()
+
+	return func(o *options[T]) {
+		o.generation = generation
+	}
+}
+
+// WithOutstanding counts the objects checked out of the pool on the given [Outstanding],
+// from Get to Put or Discard, so a graceful shutdown can wait for them via [Outstanding.WaitIdle].
+// The same [Outstanding] can be shared by several pools to wait for all of them at once.
+// The objects never put back, or discarded, are counted forever, so WaitIdle waits for the context.
+// Be careful, each object checked out must be put back, or discarded, exactly once.
+// Will panic if outstanding is nil.
+func WithOutstanding[T any](outstanding *Outstanding) Option[T] {
+	if outstanding == nil {
+		panic("argument 'outstanding' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.outstanding = outstanding
+	}
+}
+
+// WithStrictOwnership is a debug option that records the goroutine that checked out each object,
+// and calls onViolation when the object is put back, discarded, or checked via [CheckOwner],
+// from a different goroutine, with the ids of both goroutines. The callback may panic.
+// It is only enabled on builds with the race detector, like go test -race, otherwise it is a no-op.
+// Will panic if onViolation is nil, or if T is not a pointer type.
+func WithStrictOwnership[T any](onViolation func(object T, owner, caller uint64)) Option[T] {
+	if onViolation == nil {
+		panic("callback 'onViolation' must not be nil")
+	}
+
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() != reflect.Ptr {
+		panic("type parameter 'T' must be a pointer type")
+	}
+
+	return func(o *options[T]) {
+		o.onViolation = onViolation
+	}
+}
+
+// WithTypeCheck is a debug option for pools declared over an interface type, like Pool[io.Reader]:
+// it checks that each object put back has the same dynamic type of the objects created by the constructor,
+// calling onMismatch with the foreign object, that is discarded instead reset and put back.
+// The callback may panic.
+// Will panic if onMismatch is nil, or if T is not an interface type.
+func WithTypeCheck[T any](onMismatch func(object T)) Option[T] {
+	if onMismatch == nil {
+		panic("callback 'onMismatch' must not be nil")
+	}
+
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() != reflect.Interface {
+		panic("type parameter 'T' must be an interface type")
+	}
+
+	return func(o *options[T]) {
+		o.typeCheck = &typeCheck[T]{onMismatch: onMismatch}
+	}
+}
+
+// WithHotTier keeps up to size of the most recently used objects on a small array,
+// that survives the garbage collection, before the underlying [sync.Pool].
+// Useful for latency sensitive paths, since the [sync.Pool] is cleared on each garbage collection.
+// Will panic if size is not positive.
+func WithHotTier[T any](size int) Option[T] {
+	if size <= 0 {
+		panic("argument 'size' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.hotTierSize = size
+	}
+}
+
+// WithEvictionStats counts the objects reclaimed by the garbage collection while stored on the pool,
+// instead being reused, see [StatsSnapshot]. It tells if the clearing of [sync.Pool] on each garbage
+// collection is destroying the reuse rate. It must be used with [WithStats].
+// Be careful, it allocates a small wrapper on each Put.
+// It requires Go 1.24 or later (runtime.AddCleanup), otherwise it is a no-op.
+func WithEvictionStats[T any]() Option[T] {
+	return func(o *options[T]) {
+		o.evictionStats = true
+	}
+}
+
+// WithDisabled disables the pooling: Get always calls the constructor, and Put drops the object,
+// calling the callback set via [WithOnDiscard], if any. The other options have no effect, except [WithStats].
+// Useful to debug suspected state-bleed bugs. The pooling of all pools can also be disabled
+// via the environment variable [DisableEnv], like XPOOL_DISABLE=1.
+func WithDisabled[T any](disabled bool) Option[T] {
+	return func(o *options[T]) {
+		o.disabled = disabled
+	}
+}
+
+// WithChaos is a test-oriented option: it randomly drops a fraction of the objects on Put,
+// and randomly forces a call to the constructor on Get, with the given rate between 0 and 1.
+// Useful to find code that depends on the object identity or on residual state, like in soak tests.
+// The dropped objects are not observed by [WithOnDiscard], like the ones dropped by the [sync.Pool].
+// Will panic if rate is not between 0 and 1.
+func WithChaos[T any](rate float64) Option[T] {
+	if rate < 0 || rate > 1 {
+		panic("argument 'rate' must be between 0 and 1")
+	}
+
+	return func(o *options[T]) {
+		o.chaosRate = rate
+	}
+}
+
+// WithRecorder records the Get, Put, calls to the constructor and discards of the pool on the [Recorder].
+// Will panic if recorder is nil.
+func WithRecorder[T any](recorder *Recorder) Option[T] {
+	if recorder == nil {
+		panic("argument 'recorder' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.recorder = recorder
+	}
+}
+
+// WithKeepFields names the fields kept by the resetter of [NewWithAutoReset],
+// like the tag `xpool:"keep"`, for types that we can not change. Only the top-level
+// fields are accepted: the fields promoted from embedded structs panic.
+func WithKeepFields[T any](names ...string) Option[T] {
+	return func(o *options[T]) {
+		o.keepFields = append(o.keepFields, names...)
+	}
+}
+
+// WithTrimmer sets a [Trimmer] called before put the objects back to the pool, before the resetter,
+// to shrink the over-grown objects, or to drop them. The dropped objects are discarded, see [WithOnDiscard].
+// If the trimmer returns a different object, the original one is just replaced.
+// Will panic if trimmer is nil.
+func WithTrimmer[T any](trimmer Trimmer[T]) Option[T] {
+	if trimmer == nil {
+		panic("argument 'trimmer' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.trimmer = trimmer
+	}
+}
+
+// WithNilGuard sets the policy called when the constructor returns nil, like [NilPanic], [NilRetry]
+// or [NilFallback], so a nil does not sneak into the pool.
+// Will panic if policy is nil, or if T can not be nil, like a struct.
+func WithNilGuard[T any](policy NilPolicy[T]) Option[T] {
+	if policy == nil {
+		panic("argument 'policy' must not be nil")
+	}
+
+	if !canBeNil[T]() {
+		panic("type parameter 'T' must be a pointer, an interface or other type that can be nil")
+	}
+
+	return func(o *options[T]) {
+		o.nilPolicy = policy
+	}
+}
+
+// WithCtorRecover sets a callback called when the constructor panics, instead taking down the goroutine
+// that happened to hit a pool miss. The callback receives the recovered value and may return a fallback object.
+// Otherwise, the error-returning Get variants, like [GetContext], return a [CtorPanicError], and the plain Get panics with it.
+// Will panic if onPanic is nil.
+func WithCtorRecover[T any](onPanic func(recovered any) (T, bool)) Option[T] {
+	if onPanic == nil {
+		panic("callback 'onPanic' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.ctorRecover = onPanic
+	}
+}
+
+// WithLostObjects detects the objects checked out from the pool that are reclaimed by the garbage collection
+// without Put, counting them on [StatsSnapshot].Lost, see [WithStats], and releasing their slot of [WithMaxInFlight].
+// If replace is true, a new object is created and stored on the pool for each lost object,
+// so forgotten Puts degrade gracefully instead shrinking the pool.
+// It attaches a cleanup to each object on Get, via runtime.AddCleanup, so it requires Go 1.24 or later,
+// otherwise it is a no-op. It is not compatible with [WithMaxUses], [WithGeneration] and [WithMaxObjectAge], that keep a reference
+// to the objects checked out, and objects replaced by a [Trimmer] are counted as lost.
+// Will panic if T is not a pointer type.
+func WithLostObjects[T any](replace bool) Option[T] {
+	requirePointer[T]()
+
+	return func(o *options[T]) {
+		o.lostObjects = true
+		o.replaceLost = replace
+	}
+}
+
+// WithHoldTimeHistogram records how long each object stays checked out, from Get to Put or Discard,
+// in seconds, on the histogram, like one created with [DefaultHoldTimeBounds].
+// The hold time distribution is the key input to size the bounded pools, see [WithMaxInFlight].
+// The objects never put back, or discarded, are not observed, and they leak a small entry.
+// Will panic if histogram is nil, or if T is not a pointer type.
+func WithHoldTimeHistogram[T any](histogram *Histogram) Option[T] {
+	if histogram == nil {
+		panic("argument 'histogram' must not be nil")
+	}
+
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() != reflect.Ptr {
+		panic("type parameter 'T' must be a pointer type")
+	}
+
+	return func(o *options[T]) {
+		o.holdTimes = histogram
+	}
+}
+
+// WithClock sets the [Clock] used by the time-based options, like [WithHoldTimeHistogram] and [WithMaxObjectAge],
+// for instance a fake clock on tests. By default, it uses [SystemClock].
+// Will panic if clock is nil.
+func WithClock[T any](clock Clock) Option[T] {
+	if clock == nil {
+		panic("argument 'clock' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.clock = clock
+	}
+}
+
+// requirePointer panics if T is not a pointer type, for the options that track the objects by their identity:
+// the pointers are always comparable, and two distinct objects are never equal.
+func requirePointer[T any]() {
+	if reflect.TypeOf((*T)(nil)).Elem().Kind() != reflect.Ptr {
+		panic("type parameter 'T' must be a pointer type")
+	}
+}
@@ -0,0 +1,371 @@
+package xpool_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+func TestWithOnPutResetCallback(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	pool := xpool.NewWithResetter(sha256.New,
+		xpool.WithOnPutResetCallback(func(h hash.Hash, err error) {
+			calls++
+
+			assert.NoError(t, err)
+		}),
+	)
+
+	hasher := pool.Get()
+	pool.Put(hasher)
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithOnPutResetCallbackNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithOnPutResetCallback[hash.Hash](nil)
+	}, "must panic")
+}
+
+type closableCounter struct {
+	fallibleCounter
+	closed bool
+}
+
+func (c *closableCounter) Close() error {
+	c.closed = true
+
+	return nil
+}
+
+func TestWithOnDiscard(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*closableCounter
+
+	pool := xpool.NewWithCustomResetterE(func() *closableCounter {
+		return new(closableCounter)
+	}, func(c *closableCounter) error {
+		return c.Reset()
+	}, xpool.WithOnDiscard(func(c *closableCounter) {
+		discarded = append(discarded, c)
+	}))
+
+	counter := pool.Get()
+	pool.Put(counter)
+
+	assert.Empty(t, discarded)
+
+	broken := &closableCounter{fallibleCounter: fallibleCounter{broken: true}}
+	pool.Put(broken)
+
+	assert.Equal(t, []*closableCounter{broken}, discarded)
+}
+
+func TestWithOnDiscardNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithOnDiscard[hash.Hash](nil)
+	}, "must panic")
+}
+
+type tokenLimiter chan struct{}
+
+func (l tokenLimiter) Wait(ctx context.Context) error {
+	select {
+	case <-l:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type brokenLimiter struct{}
+
+func (brokenLimiter) Wait(context.Context) error {
+	return errors.New("broken limiter")
+}
+
+func TestWithCtorLimiter(t *testing.T) {
+	t.Parallel()
+
+	limiter := make(tokenLimiter, 1)
+	limiter <- struct{}{}
+
+	var ctorCalls int
+
+	pool := xpool.New(func() *bytes.Buffer {
+		ctorCalls++
+
+		return new(bytes.Buffer)
+	}, xpool.WithCtorLimiter[*bytes.Buffer](limiter))
+
+	buffer, err := xpool.GetContext(context.Background(), pool)
+	require.NoError(t, err)
+	require.NotNil(t, buffer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = xpool.GetContext(ctx, pool)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, ctorCalls, "must not call the constructor without a token")
+
+	limiter <- struct{}{}
+
+	other, err := xpool.GetContext(context.Background(), pool)
+	require.NoError(t, err)
+	assert.NotSame(t, buffer, other)
+	assert.Equal(t, 2, ctorCalls)
+}
+
+func TestWithCtorLimiterGetFailOpen(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithCtorLimiter[*bytes.Buffer](brokenLimiter{}))
+
+	assert.NotNil(t, pool.Get(), "plain Get must create the object anyway")
+
+	_, err := xpool.GetContext(context.Background(), pool)
+	require.EqualError(t, err, "broken limiter")
+}
+
+func TestWithCtorLimiterNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithCtorLimiter[*bytes.Buffer](nil)
+	}, "must panic")
+}
+
+func TestWithMaxInFlight(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithMaxInFlight[*bytes.Buffer](2))
+
+	first := pool.Get()
+	second, err := xpool.GetContext(context.Background(), pool)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = xpool.GetContext(ctx, pool)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done := make(chan *bytes.Buffer)
+
+	go func() {
+		done <- pool.Get() // must block until some object is put back
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("must block")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	pool.Put(first)
+
+	third := <-done
+
+	pool.Put(second)
+	pool.Put(third)
+}
+
+func TestWithMaxInFlightDiscard(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetterE(func() *fallibleCounter {
+		return new(fallibleCounter)
+	}, xpool.WithMaxInFlight[*fallibleCounter](1))
+
+	counter := pool.Get()
+	counter.broken = true
+	pool.Put(counter) // discarded, must release the slot anyway
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := xpool.GetContext(ctx, pool)
+	require.NoError(t, err)
+}
+
+func TestWithMaxInFlightInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithMaxInFlight[*bytes.Buffer](0)
+	}, "must panic")
+}
+
+func TestWithMaxUses(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*closableCounter
+
+	pool := xpool.NewWithResetterE(func() *closableCounter {
+		return new(closableCounter)
+	}, xpool.WithMaxUses[*closableCounter](2),
+		xpool.WithHotTier[*closableCounter](1), // the sync.Pool may drop the idle object
+		xpool.WithOnDiscard(func(c *closableCounter) {
+			discarded = append(discarded, c)
+			_ = c.Close()
+		}))
+
+	counter := pool.Get()
+	pool.Put(counter)
+
+	again := pool.Get()
+	require.Same(t, counter, again)
+
+	pool.Put(again) // second use, must be retired
+
+	require.Len(t, discarded, 1)
+	assert.Same(t, counter, discarded[0])
+	assert.True(t, counter.closed, "must call the close hook")
+
+	assert.NotSame(t, counter, pool.Get())
+}
+
+func TestWithMaxUsesResetFailure(t *testing.T) {
+	t.Parallel()
+
+	var discards int
+
+	pool := xpool.NewWithResetterE(func() *fallibleCounter {
+		return new(fallibleCounter)
+	}, xpool.WithMaxUses[*fallibleCounter](1), xpool.WithOnDiscard(func(*fallibleCounter) {
+		discards++
+	}))
+
+	counter := pool.Get()
+	counter.broken = true
+	pool.Put(counter)
+
+	assert.Equal(t, 1, discards, "must discard only once")
+}
+
+func TestWithMaxUsesInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithMaxUses[*bytes.Buffer](0)
+	}, "must panic")
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		xpool.WithMaxUses[[]byte](1)
+	}, "must panic if T is not comparable")
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		xpool.WithMaxUses[bytes.Buffer](1)
+	}, "must panic if equal objects may collide")
+}
+
+func TestWithMaxObjectAge(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+
+	var discarded []*closableCounter
+
+	pool := xpool.New(func() *closableCounter {
+		return new(closableCounter)
+	},
+		xpool.WithMaxObjectAge[*closableCounter](15*time.Minute),
+		xpool.WithClock[*closableCounter](clock),
+		xpool.WithHotTier[*closableCounter](1), // the sync.Pool may drop the idle object
+		xpool.WithOnDiscard(func(c *closableCounter) {
+			discarded = append(discarded, c)
+		}),
+	)
+
+	counter := pool.Get()
+
+	clock.Advance(10 * time.Minute)
+
+	pool.Put(counter) // still fresh
+
+	again := pool.Get()
+	require.Same(t, counter, again)
+
+	pool.Put(again)
+
+	clock.Advance(10 * time.Minute)
+
+	fresh := pool.Get() // expired while idle, must be replaced
+	assert.NotSame(t, counter, fresh)
+	assert.Equal(t, []*closableCounter{counter}, discarded)
+
+	clock.Advance(15 * time.Minute)
+
+	pool.Put(fresh) // expired while checked out, must be discarded on put
+
+	assert.Equal(t, []*closableCounter{counter, fresh}, discarded)
+}
+
+func TestWithMaxObjectAgeInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'maxAge' must be positive", func() {
+		xpool.WithMaxObjectAge[*bytes.Buffer](0)
+	})
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		xpool.WithMaxObjectAge[[]byte](time.Minute)
+	}, "must panic if T is not comparable")
+}
+
+func TestWithGetValidator(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*fallibleCounter
+
+	pool := xpool.New(func() *fallibleCounter {
+		return new(fallibleCounter)
+	}, xpool.WithGetValidator(func(c *fallibleCounter) bool {
+		return !c.broken
+	}), xpool.WithHotTier[*fallibleCounter](1), // the sync.Pool may drop the idle object
+		xpool.WithOnDiscard(func(c *fallibleCounter) {
+			discarded = append(discarded, c)
+		}))
+
+	stale := pool.Get()
+	stale.broken = true // e.g. the connection was closed while idle
+	pool.Put(stale)
+
+	fresh := pool.Get()
+	assert.NotSame(t, stale, fresh)
+	assert.False(t, fresh.broken)
+
+	assert.Equal(t, []*fallibleCounter{stale}, discarded)
+}
+
+func TestWithGetValidatorNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithGetValidator[*bytes.Buffer](nil)
+	}, "must panic")
+}
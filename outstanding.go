@@ -0,0 +1,123 @@
+package xpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Outstanding counts the objects checked out of one or more pools, enabled via [WithOutstanding],
+// so a graceful shutdown can wait until all of them are returned.
+// The zero value is ready to use and it is safe for concurrent use.
+type Outstanding struct {
+	mu    sync.Mutex
+	count int
+	idle  chan struct{} // closed when the count drops to zero, created on demand by WaitIdle.
+}
+
+// InFlight returns the number of objects checked out, not yet returned via Put or Discard.
+func (o *Outstanding) InFlight() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return o.count
+}
+
+// WaitIdle blocks until all objects checked out are returned, or the context is done,
+// returning the error of the context.
+func (o *Outstanding) WaitIdle(ctx context.Context) error {
+	o.mu.Lock()
+
+	if o.count == 0 {
+		o.mu.Unlock()
+
+		return nil
+	}
+
+	if o.idle == nil {
+		o.idle = make(chan struct{})
+	}
+
+	idle := o.idle
+
+	o.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (o *Outstanding) checkOut() {
+	o.mu.Lock()
+	o.count++
+	o.mu.Unlock()
+}
+
+func (o *Outstanding) checkIn() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.count == 0 {
+		return // an object that was not checked out, like a Put of a fresh object.
+	}
+
+	o.count--
+
+	if o.count == 0 && o.idle != nil {
+		close(o.idle)
+		o.idle = nil
+	}
+}
+
+// outstandingPool counts the objects checked out, from Get to Put or Discard.
+type outstandingPool[T any] struct {
+	pool        basePool[T]
+	outstanding *Outstanding
+}
+
+func newOutstandingPool[T any](pool basePool[T], o *options[T]) basePool[T] {
+	if o.outstanding == nil {
+		return pool
+	}
+
+	return &outstandingPool[T]{
+		pool:        pool,
+		outstanding: o.outstanding,
+	}
+}
+
+func (p *outstandingPool[T]) Get() T {
+	object := p.pool.Get()
+
+	p.outstanding.checkOut()
+
+	return object
+}
+
+func (p *outstandingPool[T]) GetContext(ctx context.Context) (T, error) {
+	object, err := p.pool.GetContext(ctx)
+	if err == nil {
+		p.outstanding.checkOut()
+	}
+
+	return object, err
+}
+
+// Put returns the object to the pool before the check in, so WaitIdle returns after the object is stored.
+func (p *outstandingPool[T]) Put(object T) {
+	defer p.outstanding.checkIn()
+
+	p.pool.Put(object)
+}
+
+func (p *outstandingPool[T]) resetOnPut(object T) error {
+	return resetOnPut[T](p.pool, object)
+}
+
+func (p *outstandingPool[T]) Discard(object T) {
+	defer p.outstanding.checkIn()
+
+	p.pool.Discard(object)
+}
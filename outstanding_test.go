@@ -0,0 +1,83 @@
+package xpool_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithOutstanding(t *testing.T) {
+	t.Parallel()
+
+	var outstanding xpool.Outstanding
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOutstanding[*bytes.Buffer](&outstanding))
+
+	require.NoError(t, outstanding.WaitIdle(context.Background()))
+
+	first := pool.Get()
+	second, err := xpool.GetContext(context.Background(), pool)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, outstanding.InFlight())
+
+	pool.Put(first)
+	assert.Equal(t, 1, outstanding.InFlight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, outstanding.WaitIdle(ctx), context.DeadlineExceeded)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- outstanding.WaitIdle(context.Background())
+	}()
+
+	xpool.Discard(pool, second)
+
+	require.NoError(t, <-done)
+	assert.Equal(t, 0, outstanding.InFlight())
+
+	pool.Put(new(bytes.Buffer)) // never checked out, must be ignored
+	assert.Equal(t, 0, outstanding.InFlight())
+}
+
+func TestWithOutstandingShared(t *testing.T) {
+	t.Parallel()
+
+	var outstanding xpool.Outstanding
+
+	buffers := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOutstanding[*bytes.Buffer](&outstanding))
+
+	readers := xpool.New(func() *bytes.Reader {
+		return bytes.NewReader(nil)
+	}, xpool.WithOutstanding[*bytes.Reader](&outstanding), xpool.WithDisabled[*bytes.Reader](true))
+
+	buf, reader := buffers.Get(), readers.Get()
+	assert.Equal(t, 2, outstanding.InFlight())
+
+	buffers.Put(buf)
+	readers.Put(reader)
+
+	require.NoError(t, outstanding.WaitIdle(context.Background()))
+}
+
+func TestWithOutstandingNil(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'outstanding' must not be nil", func() {
+		xpool.WithOutstanding[*bytes.Buffer](nil)
+	})
+}
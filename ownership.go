@@ -0,0 +1,121 @@
+package xpool
+
+import (
+	"context"
+	"sync"
+)
+
+// ownerChecker is implemented by the pools created with [WithStrictOwnership].
+type ownerChecker[T any] interface {
+	checkOwner(object T)
+}
+
+// CheckOwner flags the access to an object checked out by another goroutine, calling the callback
+// set via [WithStrictOwnership], if the pool enables it. It is a no-op otherwise, so it can be called
+// from the methods of the pooled objects, like a Write, to find where the object leaked to another goroutine.
+func CheckOwner[T any](pool Pool[T], object T) {
+	if checker, ok := pool.(ownerChecker[T]); ok {
+		checker.checkOwner(object)
+	}
+}
+
+// ownedPool records the goroutine that checked out each object, and flags the Put, Discard or
+// [CheckOwner] called from a different goroutine. The objects are indexed by address,
+// so the pool does not keep them reachable.
+type ownedPool[T any] struct {
+	pool        basePool[T]
+	onViolation func(object T, owner, caller uint64)
+
+	mu     sync.Mutex
+	owners map[uintptr]uint64
+}
+
+func newOwnedPool[T any](pool basePool[T], o *options[T]) basePool[T] {
+	if o.onViolation == nil || !raceEnabled {
+		return pool
+	}
+
+	return &ownedPool[T]{
+		pool:        pool,
+		onViolation: o.onViolation,
+		owners:      make(map[uintptr]uint64),
+	}
+}
+
+func (p *ownedPool[T]) Get() T {
+	object := p.pool.Get()
+
+	p.own(object)
+
+	return object
+}
+
+func (p *ownedPool[T]) GetContext(ctx context.Context) (T, error) {
+	object, err := p.pool.GetContext(ctx)
+	if err == nil {
+		p.own(object)
+	}
+
+	return object, err
+}
+
+func (p *ownedPool[T]) Put(object T) {
+	p.disown(object)
+
+	p.pool.Put(object)
+}
+
+func (p *ownedPool[T]) resetOnPut(object T) error {
+	return resetOnPut[T](p.pool, object)
+}
+
+func (p *ownedPool[T]) Discard(object T) {
+	p.disown(object)
+
+	p.pool.Discard(object)
+}
+
+func (p *ownedPool[T]) checkOwner(object T) {
+	address := uintptr(pointerOf(object))
+	if address == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	owner, ok := p.owners[address]
+	p.mu.Unlock()
+
+	if caller := goroutineID(); ok && owner != caller {
+		p.onViolation(object, owner, caller)
+	}
+}
+
+func (p *ownedPool[T]) own(object T) {
+	address := uintptr(pointerOf(object))
+	if address == 0 {
+		return
+	}
+
+	owner := goroutineID()
+
+	p.mu.Lock()
+	p.owners[address] = owner
+	p.mu.Unlock()
+}
+
+// disown stops tracking the object, calling the callback out of the lock, since it may panic.
+func (p *ownedPool[T]) disown(object T) {
+	address := uintptr(pointerOf(object))
+	if address == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	owner, ok := p.owners[address]
+	delete(p.owners, address)
+	p.mu.Unlock()
+
+	if caller := goroutineID(); ok && owner != caller {
+		p.onViolation(object, owner, caller)
+	}
+}
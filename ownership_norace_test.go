@@ -0,0 +1,33 @@
+//go:build !race
+
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithStrictOwnershipWithoutRace(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStrictOwnership(func(*bytes.Buffer, uint64, uint64) {
+		t.Error("must be a no-op without the race detector")
+	}))
+
+	buf := pool.Get()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		xpool.CheckOwner(pool, buf)
+		pool.Put(buf)
+	}()
+
+	<-done
+}
@@ -0,0 +1,67 @@
+//go:build race
+
+package xpool_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithStrictOwnership(t *testing.T) {
+	t.Parallel()
+
+	type violation struct {
+		object        *bytes.Buffer
+		owner, caller uint64
+	}
+
+	var (
+		mu         sync.Mutex
+		violations []violation
+	)
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStrictOwnership(func(object *bytes.Buffer, owner, caller uint64) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		violations = append(violations, violation{object: object, owner: owner, caller: caller})
+	}))
+
+	local := pool.Get()
+	xpool.CheckOwner(pool, local)
+	pool.Put(local) // same goroutine, must not be flagged
+
+	require.Empty(t, violations)
+
+	shared := pool.Get()
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		xpool.CheckOwner(pool, shared)
+		pool.Put(shared)
+	}()
+
+	wg.Wait()
+
+	require.Len(t, violations, 2)
+
+	for _, v := range violations {
+		assert.Same(t, shared, v.object)
+		assert.NotZero(t, v.owner)
+		assert.NotZero(t, v.caller)
+		assert.NotEqual(t, v.owner, v.caller)
+	}
+}
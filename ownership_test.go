@@ -0,0 +1,38 @@
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithStrictOwnershipInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "callback 'onViolation' must not be nil", func() {
+		xpool.WithStrictOwnership[*bytes.Buffer](nil)
+	})
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		xpool.WithStrictOwnership(func(bytes.Buffer, uint64, uint64) {})
+	})
+}
+
+func TestCheckOwnerWithoutOption(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	buf := pool.Get()
+
+	assert.NotPanics(t, func() {
+		xpool.CheckOwner(pool, buf)
+	})
+
+	pool.Put(buf)
+}
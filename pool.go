@@ -17,7 +17,15 @@
 // Another alternative is to use https://github.com/peczenyj/xpool/monadic subpackage package.
 package xpool
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"unsafe"
+
+	"github.com/peczenyj/xpool/internal/semaphore"
+)
 
 var _ Pool[any] = (*sync.Pool)(nil)
 
@@ -27,89 +35,628 @@ var _ Pool[any] = (*sync.Pool)(nil)
 //
 // For convenience, a pointer to sync.Pool is a Pool[any]
 type Pool[T any] interface {
+	Getter[T]
+	Putter[T]
+}
+
+// Getter is the consumer side of a [Pool], for code that only fetch objects.
+type Getter[T any] interface {
 	// Get fetch one item from object pool
 	// If needed, will create another object.
 	Get() T
+}
 
+// Putter is the producer side of a [Pool], for code that only recycle objects,
+// like a producer that put back the buffers provided by the consumers.
+// It is also implemented by the monadic pools.
+type Putter[T any] interface {
 	// Put return the object to the pull.
 	// It may reset the object before put it back to sync pool.
 	Put(object T)
 }
 
+// ErrClosed is returned by the error-returning Get variants, like [GetContext], of pools that can be closed,
+// like the ring pool, when a Get races with the shutdown. The plain Get creates a new object instead, by default.
+var ErrClosed = errors.New("xpool: pool closed")
+
+// ContextGetter is implemented by pools that can respect the context cancellation
+// while waiting for an object, see [GetContext].
+type ContextGetter[T any] interface {
+	// GetContext fetch one item from object pool, like Get, or returns an error if the context is done.
+	GetContext(ctx context.Context) (T, error)
+}
+
+// GetContext fetch one item from object pool, respecting the context cancellation
+// if the pool implements [ContextGetter]. Otherwise, it calls Get.
+func GetContext[T any](ctx context.Context, pool Pool[T]) (T, error) {
+	if getter, ok := pool.(ContextGetter[T]); ok {
+		return getter.GetContext(ctx)
+	}
+
+	if err := ctx.Err(); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return pool.Get(), nil
+}
+
+// Discarder is implemented by pools that can discard an object checked out from the pool, see [Discard].
+type Discarder[T any] interface {
+	// Discard drops the object instead put it back to the pool.
+	Discard(object T)
+}
+
+// Discard drops an object checked out from the pool instead put it back, if the pool implements [Discarder],
+// calling the callback set via [WithOnDiscard] and releasing the resources tracked by the pool.
+// Useful when the object is known to be broken.
+func Discard[T any](pool Pool[T], object T) {
+	if discarder, ok := pool.(Discarder[T]); ok {
+		discarder.Discard(object)
+	}
+}
+
 // Resetter interface.
 type Resetter interface {
 	// Reset may return the object to his initial state.
 	Reset()
 }
 
+// ResetCloser interface, for objects that must be closed when discarded by the pool.
+type ResetCloser interface {
+	Resetter
+	io.Closer
+}
+
+// ResetterE interface, like [Resetter] but the Reset may fail.
+type ResetterE interface {
+	// Reset may return the object to his initial state, or an error.
+	Reset() error
+}
+
 // New is the constructor of an [Pool] for a given generic type T.
 // Receives the constructor of the type T.
+// The behavior can be customized via [Option].
 func New[T any](
 	ctor func() T,
+	opts ...Option[T],
 ) Pool[T] {
-	return &simplePool[T]{
-		pool: new(sync.Pool),
-		ctor: ctor,
+	o := buildOptions(opts)
+
+	return wrapPool(newTrimmedPool(newBasePool(ctor, o), o.trimmer), o)
+}
+
+// basePool is the pool that stores the objects, under the resettable pool.
+type basePool[T any] interface {
+	Pool[T]
+	ContextGetter[T]
+	Discarder[T]
+}
+
+// wrapPool adds the options that observe the objects checked out, from Get to Put or Discard.
+func wrapPool[T any](pool basePool[T], o *options[T]) basePool[T] {
+	return newOwnedPool(newOutstandingPool(newHeldPool(newTypedPool(pool, o), o), o), o)
+}
+
+func newBasePool[T any](ctor func() T, o *options[T]) basePool[T] {
+	if o.ctorRecover != nil {
+		ctor = recoverCtor(ctor, o.ctorRecover)
+	}
+
+	if o.nilPolicy != nil {
+		ctor = guardNil(ctor, o.nilPolicy)
+	}
+
+	if o.typeCheck != nil {
+		ctor = o.typeCheck.learn(ctor)
+	}
+
+	var pool basePool[T]
+	if o.recorder != nil {
+		pool = newRecordedPool(ctor, o)
+	} else {
+		pool = newStoragePool(ctor, o)
+	}
+
+	if o.ctorRecover != nil {
+		return &recoveredPool[T]{pool}
+	}
+
+	return pool
+}
+
+// newStoragePool returns the fast path when no option needs to intercept the pool misses,
+// and T does not need to be boxed, see [needsBoxing].
+func newStoragePool[T any](ctor func() T, o *options[T]) basePool[T] {
+	if o.isDisabled() {
+		return newDisabledPool(ctor, o)
+	}
+
+	if o.interceptsMisses() || needsBoxing[T]() {
+		return newSimplePool(ctor, o)
+	}
+
+	return newFastPool(ctor, o)
+}
+
+func newSimplePool[T any](ctor func() T, o *options[T]) *simplePool[T] {
+	var backend Pool[any] = new(sync.Pool)
+	if o.hotTierSize > 0 {
+		backend = newHotTier(o.hotTierSize)
+	}
+
+	if o.evictionStats {
+		backend = newEvictionBackend(backend, o.stats)
+	}
+
+	if o.chaosRate > 0 {
+		backend = newChaosBackend(backend, o.chaosRate)
+	}
+
+	p := &simplePool[T]{
+		pool:        backend,
+		ctor:        ctor,
+		ctorLimiter: o.ctorLimiter,
+		inFlight:    semaphore.New(o.maxInFlight),
+		tracker:     newTracker(o),
+		boxer:       newBoxer[T](),
+		validator:   o.getValidator,
+		onDiscard:   o.onDiscard,
+		stats:       o.stats,
+	}
+
+	if o.lostObjects {
+		p.replaceLost = o.replaceLost
+		p.lost = newLostTracker(p.onLost)
 	}
+
+	return p
 }
 
 // NewWithDefaultResetter is an alternative constructor of an [Pool] for a given generic type T.
 // We can specify a special resetter, to be called before return the object from the pool.
 // Be careful, the custom resetter must be thread safe.
 // Will panic if onPutResetter is nil.
+// The behavior can be customized via [Option].
 func NewWithCustomResetter[T any](
 	ctor func() T,
 	onPutResetter func(T),
+	opts ...Option[T],
 ) Pool[T] {
 	if onPutResetter == nil {
 		panic("callback 'onPutResetter' must not be nil")
 	}
 
-	return &resettablePool[T]{
-		pool:          New(ctor),
-		onPutResetter: onPutResetter,
+	return newResettablePool(ctor, func(object T) error {
+		onPutResetter(object)
+
+		return nil
+	}, opts)
+}
+
+// NewWithCustomResetterE is an alternative constructor of an [Pool] for a given generic type T.
+// Similar to [NewWithCustomResetter], but the custom resetter may return an error.
+// If the resetter fails, the object is discarded instead put it back to the pool,
+// the error can be observed via [WithOnPutResetCallback].
+// Be careful, the custom resetter must be thread safe.
+// Will panic if onPutResetter is nil.
+// The behavior can be customized via [Option].
+func NewWithCustomResetterE[T any](
+	ctor func() T,
+	onPutResetter func(T) error,
+	opts ...Option[T],
+) Pool[T] {
+	if onPutResetter == nil {
+		panic("callback 'onPutResetter' must not be nil")
 	}
+
+	return newResettablePool(ctor, onPutResetter, opts)
 }
 
 // NewWithResetter is an alternative constructor of an [Pool] for a given generic type T.
 // T must be a [Resetter], before put the object back to object pool we will call Reset().
+// The behavior can be customized via [Option].
 func NewWithResetter[T Resetter](
 	ctor func() T,
+	opts ...Option[T],
 ) Pool[T] {
 	return NewWithCustomResetter(ctor, func(object T) {
 		object.Reset()
-	})
+	}, opts...)
+}
+
+// NewWithResetterE is an alternative constructor of an [Pool] for a given generic type T.
+// T must be a [ResetterE], before put the object back to object pool we will call Reset().
+// If Reset fails, the object is discarded instead put it back to the pool.
+// The behavior can be customized via [Option].
+func NewWithResetterE[T ResetterE](
+	ctor func() T,
+	opts ...Option[T],
+) Pool[T] {
+	return NewWithCustomResetterE(ctor, func(object T) error {
+		return object.Reset()
+	}, opts...)
+}
+
+// NewWithResetCloser is an alternative constructor of an [Pool] for a given generic type T.
+// T must be a [ResetCloser], before put the object back to object pool we will call Reset(),
+// and when the pool discards the object we will call Close(), before the callback set via [WithOnDiscard], if any.
+// The behavior can be customized via [Option].
+func NewWithResetCloser[T ResetCloser](
+	ctor func() T,
+	opts ...Option[T],
+) Pool[T] {
+	// the close is applied last, so it is chained with the callback set via [WithOnDiscard], if any.
+	return NewWithResetter(ctor, append(opts[:len(opts):len(opts)], closeOnDiscard[T]())...)
+}
+
+// Cloner interface, for objects that can be copied from a prototype.
+type Cloner[T any] interface {
+	// Clone returns an independent copy of the object.
+	Clone() T
+}
+
+// NewFromPrototype is an alternative constructor of an [Pool] for a given generic type T.
+// Instead a constructor, it receives a prototype: when the pool is empty we will call proto.Clone().
+// Useful when the object is built from an expensive template, like pre-parsed tables.
+// Be careful, the Clone method must be thread safe and the prototype must not be modified.
+// The behavior can be customized via [Option].
+func NewFromPrototype[T Cloner[T]](
+	proto T,
+	opts ...Option[T],
+) Pool[T] {
+	return New(proto.Clone, opts...)
+}
+
+// closeOnDiscard calls Close on the discarded objects, before the callback set via [WithOnDiscard], if any.
+func closeOnDiscard[T io.Closer]() Option[T] {
+	return func(o *options[T]) {
+		onDiscard := o.onDiscard
+
+		o.onDiscard = func(object T) {
+			_ = object.Close()
+
+			if onDiscard != nil {
+				onDiscard(object)
+			}
+		}
+	}
+}
+
+func newResettablePool[T any](
+	ctor func() T,
+	onPutResetter func(T) error,
+	opts []Option[T],
+) Pool[T] {
+	o := buildOptions(opts)
+
+	if o.isDisabled() {
+		// there is no need to reset, or trim, the objects that will be dropped.
+		return wrapPool(newBasePool(ctor, o), o)
+	}
+
+	if onPutCallback := o.onPutCallback; onPutCallback != nil {
+		innerOnPutResetter := onPutResetter
+		onPutResetter = func(object T) error {
+			err := innerOnPutResetter(object)
+
+			onPutCallback(object, err)
+
+			return err
+		}
+	}
+
+	return wrapPool(newTrimmedPool[T](&resettablePool[T]{
+		pool:          newBasePool(ctor, o),
+		onPutResetter: onPutResetter,
+		stats:         o.stats,
+	}, o.trimmer), o)
+}
+
+// fastPool relies on the New field of [sync.Pool] to create the objects,
+// so Get does not need to check if the pool is empty.
+type fastPool[T any] struct {
+	pool      sync.Pool
+	onDiscard func(T)
+	stats     *Stats
+}
+
+func newFastPool[T any](ctor func() T, o *options[T]) *fastPool[T] {
+	p := &fastPool[T]{
+		onDiscard: o.onDiscard,
+		stats:     o.stats,
+	}
+
+	stats := o.stats
+	p.pool.New = func() any {
+		stats.incNews()
+
+		return ctor()
+	}
+
+	return p
+}
+
+func (p *fastPool[T]) Get() T {
+	p.stats.incGets()
+
+	// the pool only stores values of type T, or calls the constructor.
+	// the comma-ok form accepts a nil interface, when T is an interface type.
+	object, _ := p.pool.Get().(T)
+
+	return object
+}
+
+func (p *fastPool[T]) GetContext(ctx context.Context) (T, error) {
+	if err := ctx.Err(); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return p.Get(), nil
 }
 
+func (p *fastPool[T]) Put(object T) {
+	p.stats.incPuts()
+
+	p.pool.Put(object)
+}
+
+func (p *fastPool[T]) Discard(object T) {
+	if p.onDiscard != nil {
+		p.onDiscard(object)
+	}
+}
+
+// simplePool supports the options that intercept the pool misses, like [WithCtorLimiter].
 type simplePool[T any] struct {
-	pool Pool[any]
-	ctor func() T
+	pool        Pool[any]
+	ctor        func() T
+	ctorLimiter Limiter
+	inFlight    semaphore.Semaphore
+	tracker     *tracker[T]
+	boxer       *boxer[T]
+	validator   func(T) bool
+	onDiscard   func(T)
+	stats       *Stats
+	lost        *lostTracker
+	replaceLost bool
 }
 
 func (p *simplePool[T]) Get() T {
-	object, ok := p.pool.Get().(T)
-	if !ok {
-		object = p.ctor()
+	// without a deadline the semaphore will wait forever, and the limiter should not fail.
+	// if the limiter fails we create the object anyway.
+	_ = p.inFlight.Acquire(context.Background())
+
+	p.stats.incGets()
+
+	if object, ok := p.fetch(); ok {
+		return p.checkOut(object)
+	}
+
+	if p.ctorLimiter != nil {
+		_ = p.ctorLimiter.Wait(context.Background())
+	}
+
+	return p.checkOut(p.newObject())
+}
+
+func (p *simplePool[T]) GetContext(ctx context.Context) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if err := p.inFlight.Acquire(ctx); err != nil {
+		return zero, err
+	}
+
+	p.stats.incGets()
+
+	if object, ok := p.fetch(); ok {
+		return p.checkOut(object), nil
+	}
+
+	if p.ctorLimiter != nil {
+		if err := p.ctorLimiter.Wait(ctx); err != nil {
+			p.inFlight.Release()
+
+			return zero, err
+		}
+	}
+
+	return p.checkOut(p.newObject()), nil
+}
+
+// checkOut attaches the cleanup that detects the lost objects, see [WithLostObjects].
+func (p *simplePool[T]) checkOut(object T) T {
+	if p.lost != nil {
+		p.lost.checkOut(pointerOf(object))
+	}
+
+	return object
+}
+
+// pointerOf returns the address of the object, T must be a pointer type, see [WithLostObjects].
+func pointerOf[T any](object T) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&object))
+}
+
+// onLost is called when an object checked out from the pool is reclaimed by the garbage collection without Put.
+func (p *simplePool[T]) onLost() {
+	p.stats.incLost()
+	p.inFlight.Release()
+
+	if p.replaceLost {
+		p.stats.incNews()
+		p.store(p.ctor())
+	}
+}
+
+func (p *simplePool[T]) fetch() (T, bool) {
+	for {
+		object, current, ok := p.fetchOne()
+		if !ok {
+			return object, false
+		}
+
+		if current && (p.validator == nil || p.validator(object)) {
+			return object, true
+		}
+
+		// discard the stale object, and try the next one.
+		if p.tracker != nil {
+			p.tracker.forget(object)
+		}
+
+		p.discard(object)
 	}
+}
+
+func (p *simplePool[T]) fetchOne() (object T, current, ok bool) {
+	switch {
+	case p.tracker != nil:
+		return p.tracker.checkOut(p.pool.Get())
+	case p.boxer != nil:
+		object, ok = p.boxer.unwrap(p.pool.Get())
+	default:
+		object, ok = p.pool.Get().(T)
+	}
+
+	return object, true, ok
+}
+
+func (p *simplePool[T]) newObject() T {
+	p.stats.incNews()
+
+	if p.inFlight == nil {
+		return p.construct()
+	}
+
+	// if the constructor panics, the object will never be put back.
+	created := false
+
+	defer func() {
+		if !created {
+			p.inFlight.Release()
+		}
+	}()
+
+	object := p.construct()
+	created = true
+
+	return object
+}
+
+func (p *simplePool[T]) construct() T {
+	if p.tracker == nil {
+		return p.ctor()
+	}
+
+	// the generation must be read before call the constructor, that may change with the generation.
+	generation, created := p.tracker.generation.current(), p.tracker.now()
+
+	object := p.ctor()
+
+	p.tracker.checkOutNew(object, generation, created)
 
 	return object
 }
 
 func (p *simplePool[T]) Put(object T) {
+	defer p.inFlight.Release()
+
+	p.stats.incPuts()
+
+	if p.lost != nil {
+		p.lost.checkIn(pointerOf(object))
+	}
+
+	p.store(object)
+}
+
+func (p *simplePool[T]) store(object T) {
+	if p.tracker != nil {
+		value, ok := p.tracker.checkIn(object)
+		if !ok {
+			p.discard(object)
+
+			return
+		}
+
+		p.pool.Put(value)
+
+		return
+	}
+
+	if p.boxer != nil {
+		p.pool.Put(p.boxer.wrap(object))
+
+		return
+	}
+
 	p.pool.Put(object)
 }
 
+func (p *simplePool[T]) Discard(object T) {
+	if p.lost != nil {
+		p.lost.checkIn(pointerOf(object))
+	}
+
+	if p.tracker != nil {
+		p.tracker.forget(object)
+	}
+
+	p.discard(object)
+	p.inFlight.Release()
+}
+
+func (p *simplePool[T]) discard(object T) {
+	if p.onDiscard != nil {
+		p.onDiscard(object)
+	}
+}
+
 type resettablePool[T any] struct {
-	pool          Pool[T]
-	onPutResetter func(T)
+	pool          basePool[T]
+	onPutResetter func(T) error
+	stats         *Stats
 }
 
 func (p *resettablePool[T]) Get() T {
 	return p.pool.Get()
 }
 
+func (p *resettablePool[T]) GetContext(ctx context.Context) (T, error) {
+	return p.pool.GetContext(ctx)
+}
+
 func (p *resettablePool[T]) Put(object T) {
-	p.onPutResetter(object)
+	if err := p.onPutResetter(object); err != nil {
+		// discard the object, the inner pool will not count this put.
+		p.stats.incPuts()
+		p.stats.incResetFailures()
+
+		p.pool.Discard(object)
+
+		return
+	}
 
 	p.pool.Put(object)
 }
+
+func (p *resettablePool[T]) resetOnPut(object T) error {
+	err := p.onPutResetter(object)
+	if err != nil {
+		p.stats.incResetFailures()
+	}
+
+	return err
+}
+
+func (p *resettablePool[T]) Discard(object T) {
+	p.pool.Discard(object)
+}
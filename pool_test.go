@@ -2,7 +2,9 @@ package xpool_test
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"hash"
 	"io"
@@ -63,6 +65,54 @@ func TestXPoolMultipleGets(t *testing.T) {
 	assert.NotSame(t, rw1, rw2)
 }
 
+func TestXPoolNilInterface(t *testing.T) {
+	t.Parallel()
+
+	// the constructor may return a nil interface.
+	pool := xpool.New(func() io.Reader {
+		return nil
+	})
+
+	assert.NotPanics(t, func() {
+		r := pool.Get()
+		assert.Nil(t, r)
+	})
+}
+
+// recycler only needs to put back the buffers, it depends on xpool.Putter.
+type recycler struct {
+	putted []*bytes.Buffer
+}
+
+func (r *recycler) Put(buf *bytes.Buffer) {
+	r.putted = append(r.putted, buf)
+}
+
+func TestGetterPutter(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	var (
+		getter xpool.Getter[*bytes.Buffer] = pool
+		putter xpool.Putter[*bytes.Buffer] = pool
+	)
+
+	buf := getter.Get()
+	require.NotNil(t, buf)
+
+	putter.Put(buf)
+
+	mock := new(recycler)
+
+	putter = mock
+	putter.Put(buf)
+
+	assert.Equal(t, []*bytes.Buffer{buf}, mock.putted)
+}
+
 func TestResetter(t *testing.T) {
 	t.Parallel()
 
@@ -167,3 +217,258 @@ func ExampleNewWithResetter() {
 	// Output:
 	// 239f59ed55e737c77147cf55ad0c1b030b6d7ee748a7426952f9b852d5a935e5
 }
+
+// requireReused puts the checked out object back to the pool and gets one, until the pool returns
+// the same object, that is returned. Each attempt puts back only the object taken out by the previous one,
+// so no object is put twice. The sync.Pool drops objects at random, specially under the race detector,
+// so a single round trip is not deterministic, while missing all the attempts is negligible.
+func requireReused[T comparable](t *testing.T, put func(T), get func() T, object T) T {
+	t.Helper()
+
+	for i := 0; i < 100; i++ {
+		put(object)
+
+		got := get()
+		if got == object {
+			return got
+		}
+
+		object = got // the object was dropped, retry with the new one.
+	}
+
+	t.Fatal("the pool never reused the object")
+
+	return object
+}
+
+type fallibleCounter struct {
+	count  int
+	broken bool
+}
+
+func (c *fallibleCounter) Reset() error {
+	if c.broken {
+		return errors.New("broken counter")
+	}
+
+	c.count = 0
+
+	return nil
+}
+
+func TestNewWithResetterE(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stats  xpool.Stats
+		errors []error
+	)
+
+	pool := xpool.NewWithResetterE(func() *fallibleCounter {
+		return new(fallibleCounter)
+	},
+		xpool.WithStats[*fallibleCounter](&stats),
+		xpool.WithOnPutResetCallback(func(_ *fallibleCounter, err error) {
+			errors = append(errors, err)
+		}),
+	)
+
+	counter := pool.Get()
+	counter.count++
+
+	pool.Put(counter)
+	assert.Zero(t, counter.count)
+
+	broken := &fallibleCounter{broken: true}
+
+	pool.Put(broken) // must be discarded
+
+	for i := 0; i < 3; i++ {
+		assert.NotSame(t, broken, pool.Get())
+	}
+
+	require.Len(t, errors, 2)
+	require.NoError(t, errors[0])
+	require.EqualError(t, errors[1], "broken counter")
+
+	assert.EqualValues(t, 1, stats.Snapshot().ResetFailures)
+	assert.EqualValues(t, 2, stats.Snapshot().Puts)
+}
+
+func TestNewWithCustomResetterE(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.NewWithCustomResetterE[*fallibleCounter](nil, nil)
+	}, "must panic")
+}
+
+type resetCloser struct {
+	resets int
+	closed bool
+}
+
+func (r *resetCloser) Reset() { r.resets++ }
+
+func (r *resetCloser) Close() error {
+	r.closed = true
+
+	return nil
+}
+
+func TestNewWithResetCloser(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetCloser(func() *resetCloser {
+		return new(resetCloser)
+	})
+
+	object := pool.Get()
+	pool.Put(object)
+
+	assert.Equal(t, 1, object.resets)
+	assert.False(t, object.closed, "must not close on a regular put")
+}
+
+func TestNewWithResetCloserOnDiscard(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*resetCloser
+
+	pool := xpool.NewWithResetCloser(func() *resetCloser {
+		return new(resetCloser)
+	}, xpool.WithOnDiscard(func(object *resetCloser) {
+		assert.True(t, object.closed, "must close before the callback")
+
+		discarded = append(discarded, object)
+	}))
+
+	object := pool.Get()
+	xpool.Discard(pool, object)
+
+	assert.True(t, object.closed, "must close on discard")
+	assert.Equal(t, []*resetCloser{object}, discarded)
+}
+
+func TestGetContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for name, pool := range map[string]xpool.Pool[*bytes.Buffer]{
+		"new": xpool.New(func() *bytes.Buffer {
+			return new(bytes.Buffer)
+		}),
+		"resetter": xpool.NewWithResetter(func() *bytes.Buffer {
+			return new(bytes.Buffer)
+		}),
+		"custom": customBufferPool{},
+	} {
+		pool := pool
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			buffer, err := xpool.GetContext(context.Background(), pool)
+			require.NoError(t, err)
+			assert.NotNil(t, buffer)
+
+			_, err = xpool.GetContext(ctx, pool)
+			require.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
+
+type customBufferPool struct{}
+
+func (customBufferPool) Get() *bytes.Buffer { return new(bytes.Buffer) }
+
+func (customBufferPool) Put(*bytes.Buffer) {}
+
+type lookupTable struct {
+	entries map[string]int
+}
+
+func (t *lookupTable) Clone() *lookupTable {
+	entries := make(map[string]int, len(t.entries))
+	for k, v := range t.entries {
+		entries[k] = v
+	}
+
+	return &lookupTable{entries: entries}
+}
+
+func TestNewFromPrototype(t *testing.T) {
+	t.Parallel()
+
+	proto := &lookupTable{entries: map[string]int{"foo": 1}}
+
+	pool := xpool.NewFromPrototype(proto)
+
+	table := pool.Get()
+	require.NotSame(t, proto, table)
+	assert.Equal(t, proto.entries, table.entries)
+
+	table.entries["bar"] = 2
+
+	other := pool.Get()
+	assert.NotContains(t, other.entries, "bar")
+	assert.NotContains(t, proto.entries, "bar", "must not modify the prototype")
+}
+
+func TestDiscard(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*bytes.Buffer
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOnDiscard(func(b *bytes.Buffer) {
+		discarded = append(discarded, b)
+	}))
+
+	broken := pool.Get()
+	xpool.Discard(pool, broken)
+
+	assert.Equal(t, []*bytes.Buffer{broken}, discarded)
+
+	// pools that do not implement xpool.Discarder are ignored.
+	xpool.Discard[*bytes.Buffer](customBufferPool{}, broken)
+}
+
+func TestGetPutNoAllocations(t *testing.T) {
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	pool.Put(pool.Get()) // warm up the pool
+
+	allocs := testing.AllocsPerRun(100, func() {
+		pool.Put(pool.Get())
+	})
+
+	assert.Zero(t, allocs, "a warm pool of pointers must not allocate")
+}
+
+func BenchmarkGetPut(b *testing.B) {
+	newBuffer := func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}
+
+	for name, pool := range map[string]xpool.Pool[*bytes.Buffer]{
+		"New":             xpool.New(newBuffer),
+		"NewWithResetter": xpool.NewWithResetter(newBuffer),
+		"WithStats":       xpool.New(newBuffer, xpool.WithStats[*bytes.Buffer](new(xpool.Stats))),
+	} {
+		pool := pool
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				pool.Put(pool.Get())
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+module github.com/peczenyj/xpool/protopool
+
+go 1.23
+
+require (
+	github.com/peczenyj/xpool v0.6.0
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/protobuf v1.36.9
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
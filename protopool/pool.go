@@ -0,0 +1,49 @@
+// Package protopool offers a generic pool of protobuf messages, resetted via [proto.Reset] before put back to the pool.
+//
+// It is a separated module, so the xpool module does not depend on google.golang.org/protobuf.
+//
+//	pool := protopool.New[*pb.HelloRequest]()
+//
+//	req := pool.Get()
+//	defer pool.Put(req)
+//
+//	if err := proto.Unmarshal(payload, req); err != nil {
+//	  return err
+//	}
+//
+// The messages must not be retained after Put, like the ones referenced by a gRPC stream after the handler returns.
+package protopool
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/peczenyj/xpool"
+)
+
+// New returns a pool of protobuf messages of type T, usually a pointer to a generated message,
+// resetted via [proto.Reset] before put back to the pool.
+// The messages are created via the protobuf reflection, like [proto.Message.ProtoReflect] New.
+// The behavior can be customized via [xpool.Option].
+func New[T proto.Message](opts ...xpool.Option[T]) xpool.Pool[T] {
+	return NewWithCtor(newMessage[T], opts...)
+}
+
+// NewWithCtor is similar to [New], but it receives the constructor of the message,
+// useful to pre-allocate nested messages or when T is an interface.
+// The behavior can be customized via [xpool.Option].
+func NewWithCtor[T proto.Message](ctor func() T, opts ...xpool.Option[T]) xpool.Pool[T] {
+	return xpool.NewWithCustomResetter(ctor, resetMessage[T], opts...)
+}
+
+func newMessage[T proto.Message]() T {
+	var zero T
+
+	// the generated messages support ProtoReflect on a nil pointer.
+	message, _ := zero.ProtoReflect().New().Interface().(T)
+
+	return message
+}
+
+func resetMessage[T proto.Message](message T) {
+	proto.Reset(message)
+}
@@ -0,0 +1,69 @@
+package protopool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/protopool"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := protopool.New[*wrapperspb.StringValue](xpool.WithStats[*wrapperspb.StringValue](&stats))
+
+	message := pool.Get()
+	require.NotNil(t, message)
+	assert.Empty(t, message.GetValue())
+
+	message.Value = "foo"
+
+	pool.Put(message)
+
+	assert.Empty(t, message.GetValue(), "must be resetted on put")
+	assert.Equal(t, uint64(1), stats.Snapshot().News)
+}
+
+func TestNewUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	payload, err := proto.Marshal(wrapperspb.String("bar"))
+	require.NoError(t, err)
+
+	pool := protopool.New[*wrapperspb.StringValue]()
+
+	for range 3 {
+		message := pool.Get()
+
+		require.NoError(t, proto.Unmarshal(payload, message))
+		assert.Equal(t, "bar", message.GetValue())
+
+		pool.Put(message)
+	}
+}
+
+func TestNewWithCtor(t *testing.T) {
+	t.Parallel()
+
+	pool := protopool.NewWithCtor(func() proto.Message {
+		return new(structpb.Struct)
+	})
+
+	message := pool.Get()
+	require.IsType(t, new(structpb.Struct), message)
+
+	object, _ := message.(*structpb.Struct)
+	object.Fields = map[string]*structpb.Value{"foo": structpb.NewStringValue("bar")}
+
+	pool.Put(message)
+
+	assert.Empty(t, object.GetFields(), "must be resetted on put")
+}
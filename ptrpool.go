@@ -0,0 +1,59 @@
+package xpool
+
+import "sync"
+
+var _ Pool[*struct{}] = (*PtrPool[struct{}])(nil)
+
+// PtrPool is a pool of pointers to T, specialized for the hot paths.
+// Different than the pools returned by [New], it is a concrete type, so the calls to Get and Put
+// are not dispatched via the [Pool] interface and can be inlined, there is no constructor callback
+// and no options: the objects are allocated via new(T), and zeroed on Put.
+// A warm pool does not allocate on Get or Put. The zero value is ready to use.
+//
+//	var pool xpool.PtrPool[Request]
+//
+//	req := pool.Get()
+//	defer pool.Put(req)
+//
+// Use [New] or [NewWithCustomResetter] when the object needs a constructor, a custom resetter or any [Option].
+type PtrPool[T any] struct {
+	pool sync.Pool
+}
+
+// NewPtrPool returns an empty [PtrPool] for a given generic type T.
+func NewPtrPool[T any]() *PtrPool[T] {
+	return new(PtrPool[T])
+}
+
+// Get fetch one item from object pool, or allocates a new zero T if the pool is empty.
+func (p *PtrPool[T]) Get() *T {
+	// the pool only stores values of type *T.
+	if object, _ := p.pool.Get().(*T); object != nil {
+		return object
+	}
+
+	return new(T)
+}
+
+// Put zeroes the object and return it to the pool. Ignores nil objects.
+func (p *PtrPool[T]) Put(object *T) {
+	if object == nil {
+		return
+	}
+
+	var zero T
+
+	*object = zero
+
+	p.pool.Put(object)
+}
+
+func (p *PtrPool[T]) resetOnPut(object *T) error {
+	if object != nil {
+		var zero T
+
+		*object = zero
+	}
+
+	return nil
+}
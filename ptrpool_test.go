@@ -0,0 +1,69 @@
+package xpool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+type request struct {
+	id      int
+	payload [64]byte
+}
+
+func TestPtrPool(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewPtrPool[request]()
+
+	req := pool.Get()
+	require.NotNil(t, req)
+
+	req.id = 42
+	req.payload[0] = 1
+
+	pool.Put(req)
+	pool.Put(nil)
+
+	assert.Equal(t, request{}, *req, "must be zeroed on put")
+
+	requireReused(t, pool.Put, pool.Get, pool.Get())
+}
+
+func TestPtrPoolZeroValue(t *testing.T) {
+	t.Parallel()
+
+	var pool xpool.PtrPool[request]
+
+	req := pool.Get()
+	require.NotNil(t, req)
+
+	pool.Put(req)
+}
+
+func TestPtrPoolNoAllocations(t *testing.T) {
+	pool := xpool.NewPtrPool[request]()
+
+	pool.Put(pool.Get()) // warm up the pool
+
+	allocs := testing.AllocsPerRun(100, func() {
+		req := pool.Get()
+		req.id++
+		pool.Put(req)
+	})
+
+	assert.Zero(t, allocs, "a warm pool must not allocate")
+}
+
+func BenchmarkPtrPool(b *testing.B) {
+	pool := xpool.NewPtrPool[request]()
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		pool.Put(pool.Get())
+	}
+}
@@ -0,0 +1,206 @@
+package xpool
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Op is the operation of an [Event].
+type Op uint8
+
+const (
+	// OpGet is an object returned by Get.
+	OpGet Op = iota + 1
+	// OpPut is an object given to Put.
+	OpPut
+	// OpNew is an object returned by the constructor.
+	OpNew
+	// OpDiscard is an object discarded by the pool, see [Discard].
+	OpDiscard
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpGet:
+		return "get"
+	case OpPut:
+		return "put"
+	case OpNew:
+		return "new"
+	case OpDiscard:
+		return "discard"
+	default:
+		return "op(" + strconv.Itoa(int(op)) + ")"
+	}
+}
+
+// Event is one operation recorded by a [Recorder].
+type Event struct {
+	// Time of the operation.
+	Time time.Time
+	// Op is the operation.
+	Op Op
+	// Goroutine is the id of the goroutine that called the operation.
+	Goroutine uint64
+	// Object is the address of the object, for pointer-shaped types like pointers and maps,
+	// or zero otherwise. Useful to follow the custody of an object.
+	Object uintptr
+}
+
+// Recorder holds the last events of a [Pool] on a ring buffer, enabled via [WithRecorder].
+// Useful for post-mortem analysis, like find where the residual state of an object came from.
+// The same Recorder can be shared by several pools. It is safe for concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	events []Event
+	next   int
+	full   bool
+}
+
+// NewRecorder returns a [Recorder] that keeps the last size events.
+// Will panic if size is not positive.
+func NewRecorder(size int) *Recorder {
+	if size <= 0 {
+		panic("argument 'size' must be positive")
+	}
+
+	return &Recorder{events: make([]Event, size)}
+}
+
+func (r *Recorder) record(op Op, object uintptr) {
+	event := Event{
+		Time:      time.Now(),
+		Op:        op,
+		Goroutine: goroutineID(),
+		Object:    object,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events[r.next] = event
+
+	r.next++
+	if r.next == len(r.events) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// Events returns a copy of the recorded events, from the oldest to the newest.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Event(nil), r.events[:r.next]...)
+	}
+
+	events := make([]Event, 0, len(r.events))
+	events = append(events, r.events[r.next:]...)
+
+	return append(events, r.events[:r.next]...)
+}
+
+// WriteTo writes the recorded events to w, one per line, from the oldest to the newest.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+
+	for _, event := range r.Events() {
+		n, err := fmt.Fprintf(w, "%s goroutine=%d op=%s object=%#x\n",
+			event.Time.Format(time.RFC3339Nano), event.Goroutine, event.Op, event.Object)
+
+		total += int64(n)
+
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, nil
+}
+
+// goroutineID parses the id of the current goroutine from the header of the stack trace, like "goroutine 42 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		header = header[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+
+	return id
+}
+
+// objectID returns the address of pointer-shaped objects, or zero.
+func objectID(object any) uintptr {
+	value := reflect.ValueOf(object)
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer, reflect.Slice:
+		return value.Pointer()
+	default:
+		return 0
+	}
+}
+
+// recordedPool records the operations of the inner pool, see [WithRecorder].
+type recordedPool[T any] struct {
+	pool     basePool[T]
+	recorder *Recorder
+}
+
+func newRecordedPool[T any](ctor func() T, o *options[T]) *recordedPool[T] {
+	recorder := o.recorder
+
+	return &recordedPool[T]{
+		pool: newStoragePool(func() T {
+			object := ctor()
+
+			recorder.record(OpNew, objectID(object))
+
+			return object
+		}, o),
+		recorder: recorder,
+	}
+}
+
+func (p *recordedPool[T]) Get() T {
+	object := p.pool.Get()
+
+	p.recorder.record(OpGet, objectID(object))
+
+	return object
+}
+
+func (p *recordedPool[T]) GetContext(ctx context.Context) (T, error) {
+	object, err := p.pool.GetContext(ctx)
+	if err == nil {
+		p.recorder.record(OpGet, objectID(object))
+	}
+
+	return object, err
+}
+
+func (p *recordedPool[T]) Put(object T) {
+	p.recorder.record(OpPut, objectID(object))
+
+	p.pool.Put(object)
+}
+
+func (p *recordedPool[T]) Discard(object T) {
+	p.recorder.record(OpDiscard, objectID(object))
+
+	p.pool.Discard(object)
+}
@@ -0,0 +1,81 @@
+package xpool_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithRecorder(t *testing.T) {
+	t.Parallel()
+
+	recorder := xpool.NewRecorder(16)
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithRecorder[*bytes.Buffer](recorder))
+
+	buffer := pool.Get()
+	pool.Put(buffer)
+	xpool.Discard(pool, pool.Get())
+
+	events := recorder.Events()
+	require.GreaterOrEqual(t, len(events), 5)
+
+	assert.Equal(t, xpool.OpNew, events[0].Op)
+	assert.Equal(t, xpool.OpGet, events[1].Op)
+	assert.Equal(t, xpool.OpPut, events[2].Op)
+	assert.Equal(t, xpool.OpDiscard, events[len(events)-1].Op)
+
+	for _, event := range events[:3] {
+		assert.NotZero(t, event.Goroutine)
+		assert.NotZero(t, event.Time)
+		assert.Equal(t, events[0].Object, event.Object, "must follow the same object")
+	}
+
+	var out strings.Builder
+
+	n, err := recorder.WriteTo(&out)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(out.Len()), n)
+	assert.Contains(t, out.String(), "op=new")
+	assert.Contains(t, out.String(), "op=discard")
+}
+
+func TestRecorderRingBuffer(t *testing.T) {
+	t.Parallel()
+
+	recorder := xpool.NewRecorder(3)
+
+	pool := xpool.New(func() int {
+		return 0
+	}, xpool.WithRecorder[int](recorder))
+
+	for i := 0; i < 10; i++ {
+		pool.Put(pool.Get())
+	}
+
+	events := recorder.Events()
+	require.Len(t, events, 3)
+
+	assert.Equal(t, xpool.OpPut, events[2].Op, "must keep the newest events")
+	assert.Zero(t, events[2].Object, "value types have no object id")
+}
+
+func TestNewRecorderInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.NewRecorder(0)
+	}, "must panic")
+
+	assert.Panics(t, func() {
+		xpool.WithRecorder[int](nil)
+	}, "must panic")
+}
@@ -0,0 +1,69 @@
+package xpool
+
+import (
+	"context"
+	"fmt"
+)
+
+// CtorPanicError is the error returned by the error-returning Get variants, like [GetContext],
+// when the constructor panics and the callback set via [WithCtorRecover] does not offer a fallback.
+// The plain Get panics with it.
+type CtorPanicError struct {
+	// Recovered is the value recovered from the panic.
+	Recovered any
+}
+
+func (e *CtorPanicError) Error() string {
+	return fmt.Sprintf("xpool: the constructor panicked: %v", e.Recovered)
+}
+
+// Unwrap returns the recovered value, if it is an error.
+func (e *CtorPanicError) Unwrap() error {
+	err, _ := e.Recovered.(error)
+
+	return err
+}
+
+// recoverCtor wraps the constructor, converting a panic into the fallback object, if any,
+// or into a panic with [CtorPanicError].
+func recoverCtor[T any](ctor func() T, onPanic func(recovered any) (T, bool)) func() T {
+	return func() (object T) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			fallback, ok := onPanic(recovered)
+			if !ok {
+				panic(&CtorPanicError{Recovered: recovered})
+			}
+
+			object = fallback
+		}()
+
+		return ctor()
+	}
+}
+
+// recoveredPool converts the [CtorPanicError] into an error on GetContext, see [WithCtorRecover].
+type recoveredPool[T any] struct {
+	basePool[T]
+}
+
+func (p *recoveredPool[T]) GetContext(ctx context.Context) (object T, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			ctorErr, ok := recovered.(*CtorPanicError)
+			if !ok {
+				panic(recovered)
+			}
+
+			var zero T
+
+			object, err = zero, ctorErr
+		}
+	}()
+
+	return p.basePool.GetContext(ctx)
+}
@@ -0,0 +1,70 @@
+package xpool_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+var errBrokenCtor = errors.New("broken constructor")
+
+func panickingCtor() *bytes.Buffer {
+	panic(errBrokenCtor)
+}
+
+func TestWithCtorRecoverFallback(t *testing.T) {
+	t.Parallel()
+
+	fallback := new(bytes.Buffer)
+
+	var recovered []any
+
+	pool := xpool.NewWithResetter(panickingCtor, xpool.WithCtorRecover(func(r any) (*bytes.Buffer, bool) {
+		recovered = append(recovered, r)
+
+		return fallback, true
+	}))
+
+	assert.Same(t, fallback, pool.Get())
+	assert.Equal(t, []any{errBrokenCtor}, recovered)
+}
+
+func TestWithCtorRecoverError(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.New(panickingCtor,
+		xpool.WithCtorRecover(func(any) (*bytes.Buffer, bool) {
+			return nil, false
+		}),
+		xpool.WithMaxInFlight[*bytes.Buffer](1),
+	)
+
+	for i := 0; i < 3; i++ { // must release the in-flight slot
+		object, err := xpool.GetContext(context.Background(), pool)
+		assert.Nil(t, object)
+
+		var ctorErr *xpool.CtorPanicError
+
+		require.ErrorAs(t, err, &ctorErr)
+		assert.Equal(t, errBrokenCtor, ctorErr.Recovered)
+		require.ErrorIs(t, err, errBrokenCtor)
+	}
+
+	assert.PanicsWithError(t, "xpool: the constructor panicked: broken constructor", func() {
+		pool.Get()
+	})
+}
+
+func TestWithCtorRecoverNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithCtorRecover[*bytes.Buffer](nil)
+	}, "must panic")
+}
@@ -0,0 +1,116 @@
+// Package region offers an arena-like allocator: objects of type T are allocated into
+// chunks fetched from a [xpool.Pool], and the whole [Region] is released at once,
+// putting the chunks back to the pool.
+//
+//	nodes := region.New[Node](1024)
+//
+//	for _, batch := range batches {
+//	  r := nodes.Acquire()
+//
+//	  for _, item := range batch {
+//	    node := r.New() // a pointer to a zeroed Node, inside a pooled chunk
+//	    ...
+//	  }
+//
+//	  r.Release() // every node of the region must not be used after this point
+//	}
+//
+// It reduces the pressure on the garbage collector when a batch builds many short-lived objects.
+package region
+
+import "github.com/peczenyj/xpool"
+
+// Allocator creates regions of objects of type T, sharing a pool of chunks.
+// It is safe for concurrent use, but each [Region] must be used by one goroutine at a time.
+type Allocator[T any] struct {
+	chunks  xpool.Pool[*[]T]
+	regions xpool.Pool[*Region[T]]
+}
+
+// New returns an [Allocator] where each chunk holds chunkSize objects of type T.
+// Will panic if chunkSize is not positive.
+func New[T any](chunkSize int) *Allocator[T] {
+	if chunkSize <= 0 {
+		panic("argument 'chunkSize' must be positive")
+	}
+
+	a := &Allocator[T]{
+		chunks: xpool.NewWithCustomResetter(func() *[]T {
+			chunk := make([]T, 0, chunkSize)
+
+			return &chunk
+		}, func(chunk *[]T) {
+			// release the references held by the objects, before reuse the chunk.
+			var zero T
+
+			for i := range *chunk {
+				(*chunk)[i] = zero
+			}
+
+			*chunk = (*chunk)[:0]
+		}),
+	}
+
+	a.regions = xpool.New(func() *Region[T] {
+		return &Region[T]{allocator: a}
+	})
+
+	return a
+}
+
+// Acquire returns an empty [Region].
+func (a *Allocator[T]) Acquire() *Region[T] {
+	return a.regions.Get()
+}
+
+// Region is a set of objects of type T released at once.
+// It is not safe for concurrent use.
+type Region[T any] struct {
+	allocator *Allocator[T]
+	chunks    []*[]T
+}
+
+// New returns a pointer to a zero value of T allocated inside the region.
+// The object must not be used after Release.
+func (r *Region[T]) New() *T {
+	var chunk *[]T
+
+	if n := len(r.chunks); n > 0 {
+		chunk = r.chunks[n-1]
+	}
+
+	if chunk == nil || len(*chunk) == cap(*chunk) {
+		chunk = r.allocator.chunks.Get()
+		r.chunks = append(r.chunks, chunk)
+	}
+
+	var zero T
+
+	*chunk = append(*chunk, zero)
+
+	return &(*chunk)[len(*chunk)-1]
+}
+
+// Len returns the number of objects allocated inside the region.
+func (r *Region[T]) Len() int {
+	var total int
+
+	for _, chunk := range r.chunks {
+		total += len(*chunk)
+	}
+
+	return total
+}
+
+// Release put all chunks back to the pool, and the region itself.
+// Neither the region nor its objects must be used after Release.
+func (r *Region[T]) Release() {
+	for i, chunk := range r.chunks {
+		r.allocator.chunks.Put(chunk)
+		r.chunks[i] = nil
+	}
+
+	r.chunks = r.chunks[:0]
+
+	r.allocator.regions.Put(r)
+}
@@ -0,0 +1,91 @@
+package region_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/region"
+)
+
+type node struct {
+	value int
+	next  *node
+}
+
+func TestRegion(t *testing.T) {
+	t.Parallel()
+
+	nodes := region.New[node](4)
+
+	r := nodes.Acquire()
+
+	var head *node
+
+	for i := 0; i < 10; i++ {
+		n := r.New()
+		require.Zero(t, *n)
+
+		n.value = i
+		n.next = head
+		head = n
+	}
+
+	assert.Equal(t, 10, r.Len())
+
+	var values []int
+	for n := head; n != nil; n = n.next {
+		values = append(values, n.value)
+	}
+
+	assert.Equal(t, []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}, values)
+
+	r.Release()
+
+	other := nodes.Acquire()
+	defer other.Release()
+
+	assert.Zero(t, other.Len())
+	assert.Zero(t, *other.New(), "reused chunks must be zeroed")
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		region.New[node](0)
+	}, "must panic")
+}
+
+func BenchmarkRegion(b *testing.B) {
+	nodes := region.New[node](1024)
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		r := nodes.Acquire()
+
+		for j := 0; j < 4096; j++ {
+			r.New().value = j
+		}
+
+		r.Release()
+	}
+}
+
+func BenchmarkHeap(b *testing.B) {
+	b.ReportAllocs()
+
+	var sink *node
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 4096; j++ {
+			sink = &node{value: j, next: sink}
+		}
+
+		sink = nil
+	}
+
+	_ = sink
+}
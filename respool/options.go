@@ -0,0 +1,113 @@
+package respool
+
+import (
+	"time"
+
+	"github.com/peczenyj/xpool"
+)
+
+// DefaultMaxIdle is the default maximum number of idle resources, like database/sql, see [WithMaxIdle].
+const DefaultMaxIdle = 2
+
+// Option is a functional option to customize a resource [Pool].
+// It is parameterized on the same generic type T of the [Pool].
+type Option[T any] func(*options[T])
+
+type options[T any] struct {
+	maxOpen     int
+	maxIdle     int
+	onClose     func(resource T)
+	maxIdleTime time.Duration
+	maxLifetime time.Duration
+	clock       xpool.Clock
+}
+
+func buildOptions[T any](opts []Option[T]) *options[T] {
+	o := &options[T]{maxIdle: DefaultMaxIdle, clock: xpool.SystemClock}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithMaxOpen bounds the number of open resources, idle or checked out: Get waits for
+// a resource put back, or discarded, respecting the context cancellation.
+// By default, there is no limit.
+// Will panic if maxOpen is not positive.
+func WithMaxOpen[T any](maxOpen int) Option[T] {
+	if maxOpen <= 0 {
+		panic("argument 'maxOpen' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.maxOpen = maxOpen
+	}
+}
+
+// WithMaxIdle sets the maximum number of idle resources, the resources put back beyond it are closed,
+// see [WithOnClose]. A maxIdle less or equal to zero means no idle resources are retained.
+// By default, it is [DefaultMaxIdle].
+func WithMaxIdle[T any](maxIdle int) Option[T] {
+	return func(o *options[T]) {
+		o.maxIdle = maxIdle
+	}
+}
+
+// WithOnClose sets a callback to release a resource that the pool will not reuse,
+// like when it is discarded, when there are too many idle resources, or on Close.
+// It is called without holding the locks of the pool.
+// Be careful, the callback must be thread safe.
+// Will panic if onClose is nil.
+func WithOnClose[T any](onClose func(resource T)) Option[T] {
+	if onClose == nil {
+		panic("callback 'onClose' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.onClose = onClose
+	}
+}
+
+// WithMaxIdleTime closes the resources idle on the pool for longer than maxIdleTime, like database/sql,
+// before the server side kills them and they fail on the first use. See [WithOnClose].
+// The culling runs on a background goroutine, terminated via [Pool.Close], and on Get.
+// Will panic if maxIdleTime is not positive.
+func WithMaxIdleTime[T any](maxIdleTime time.Duration) Option[T] {
+	if maxIdleTime <= 0 {
+		panic("argument 'maxIdleTime' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.maxIdleTime = maxIdleTime
+	}
+}
+
+// WithMaxLifetime closes the resources older than maxLifetime, since their creation, like database/sql:
+// the idle ones on a background goroutine, terminated via [Pool.Close], and on Get,
+// and the checked out ones when put back. See [WithOnClose].
+// Be careful, T must be comparable, like a pointer, since the pool tracks the checked out resources.
+// Will panic if maxLifetime is not positive.
+func WithMaxLifetime[T any](maxLifetime time.Duration) Option[T] {
+	if maxLifetime <= 0 {
+		panic("argument 'maxLifetime' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.maxLifetime = maxLifetime
+	}
+}
+
+// WithClock sets the [xpool.Clock] used by [WithMaxIdleTime] and [WithMaxLifetime],
+// like a fake clock on tests. By default, it uses [xpool.SystemClock].
+// Will panic if clock is nil.
+func WithClock[T any](clock xpool.Clock) Option[T] {
+	if clock == nil {
+		panic("argument 'clock' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.clock = clock
+	}
+}
@@ -0,0 +1,507 @@
+// Package respool offers a pool of dial-like resources, like client handles and sessions,
+// whose factory may fail and must respect the context cancellation, instead pure memory objects.
+//
+//	pool := respool.New(func(ctx context.Context) (*Session, error) {
+//	  return client.NewSession(ctx)
+//	}, respool.WithMaxOpen[*Session](16), respool.WithOnClose(func(s *Session) {
+//	  _ = s.Close()
+//	}))
+//	defer pool.Close()
+//
+//	session, err := pool.Get(ctx)
+//	if err != nil {
+//	  return err
+//	}
+//	defer pool.Put(session) // or pool.Discard(session), if it is broken
+//
+// Like database/sql, the pool retains up to [DefaultMaxIdle] idle resources, see [WithMaxIdle],
+// it may bound the number of open resources, see [WithMaxOpen], and it may close the resources idle
+// for too long, or too old, see [WithMaxIdleTime] and [WithMaxLifetime].
+package respool
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/internal/maintenance"
+)
+
+var _ xpool.Putter[any] = (*Pool[any])(nil)
+
+// ErrClosed is returned by Get after Close. It is the same error of [xpool.ErrClosed].
+var ErrClosed = xpool.ErrClosed
+
+// Pool is a pool of resources created by a context-aware factory that may fail.
+type Pool[T any] struct {
+	factory     func(ctx context.Context) (T, error)
+	maxOpen     int
+	maxIdle     int
+	onClose     func(resource T)
+	maxIdleTime time.Duration
+	maxLifetime time.Duration
+	clock       xpool.Clock
+	culler      *maintenance.Maintenance
+
+	mu      sync.Mutex
+	idle    []idleResource[T]
+	created map[any]time.Time // the creation time of the resources checked out, for [WithMaxLifetime].
+	numOpen int
+	waiters []chan grant[T]
+	closed  bool
+	drained chan struct{} // closed when the last resource is closed after Close, created on demand by CloseAndWait.
+}
+
+// idleResource is a resource stored on the pool, with the time it was created and put back, if needed.
+type idleResource[T any] struct {
+	resource T
+	created  time.Time
+	since    time.Time
+}
+
+// grant is sent to a Get waiting for a slot of [WithMaxOpen]: an idle resource, or the permission to create one.
+type grant[T any] struct {
+	resource T
+	create   bool
+}
+
+// New returns a [Pool] of resources created by factory.
+// The behavior can be customized via [Option].
+// Will panic if factory is nil.
+func New[T any](factory func(ctx context.Context) (T, error), opts ...Option[T]) *Pool[T] {
+	if factory == nil {
+		panic("callback 'factory' must not be nil")
+	}
+
+	o := buildOptions(opts)
+
+	p := &Pool[T]{
+		factory:     factory,
+		maxOpen:     o.maxOpen,
+		maxIdle:     o.maxIdle,
+		onClose:     o.onClose,
+		maxIdleTime: o.maxIdleTime,
+		maxLifetime: o.maxLifetime,
+		clock:       o.clock,
+	}
+
+	if p.maxLifetime > 0 {
+		p.created = make(map[any]time.Time)
+	}
+
+	if interval := cullInterval(p.maxIdleTime, p.maxLifetime); interval > 0 {
+		p.culler = maintenance.Start(p.clock, interval, p.cull)
+	}
+
+	return p
+}
+
+// cullInterval returns the shortest of the positive durations, or zero if there is none.
+func cullInterval(maxIdleTime, maxLifetime time.Duration) time.Duration {
+	if maxIdleTime <= 0 || (maxLifetime > 0 && maxLifetime < maxIdleTime) {
+		return maxLifetime
+	}
+
+	return maxIdleTime
+}
+
+// Get fetch one idle resource from the pool, or calls the factory. If the pool reached [WithMaxOpen],
+// it waits for a resource put back. It returns an error if the context is done, if the factory fails,
+// or [ErrClosed] after Close. The expired idle resources are closed instead returned.
+func (p *Pool[T]) Get(ctx context.Context) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	now := p.now()
+
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return zero, ErrClosed
+	}
+
+	resource, ok, expired := p.popIdleLocked(now)
+	if ok {
+		p.mu.Unlock()
+		p.closeAll(expired)
+
+		return resource, nil
+	}
+
+	if p.maxOpen > 0 && p.numOpen >= p.maxOpen {
+		req := make(chan grant[T], 1)
+		p.waiters = append(p.waiters, req)
+		p.mu.Unlock()
+		p.closeAll(expired)
+
+		return p.wait(ctx, req)
+	}
+
+	p.numOpen++
+	p.mu.Unlock()
+	p.closeAll(expired)
+
+	return p.create(ctx)
+}
+
+// popIdleLocked returns the most recently used idle resource, if any, and the expired ones found before it,
+// that must be closed after unlock.
+func (p *Pool[T]) popIdleLocked(now time.Time) (resource T, ok bool, expired []T) {
+	for n := len(p.idle); n > 0; n = len(p.idle) {
+		entry := p.idle[n-1]
+		p.idle[n-1] = idleResource[T]{}
+		p.idle = p.idle[:n-1]
+
+		if p.expired(entry, now) {
+			p.closeOpenLocked(1)
+			expired = append(expired, entry.resource)
+
+			continue
+		}
+
+		p.checkOutLocked(entry.resource, entry.created)
+
+		return entry.resource, true, expired
+	}
+
+	return resource, false, expired
+}
+
+func (p *Pool[T]) wait(ctx context.Context, req chan grant[T]) (T, error) {
+	var zero T
+
+	select {
+	case g, ok := <-req:
+		if !ok {
+			return zero, ErrClosed
+		}
+
+		if g.create {
+			return p.create(ctx)
+		}
+
+		return g.resource, nil
+	case <-ctx.Done():
+		p.mu.Lock()
+		removed := p.removeWaiterLocked(req)
+		p.mu.Unlock()
+
+		// the grant was sent, under the lock, before the waiter was removed.
+		if !removed {
+			if g, ok := <-req; ok {
+				if g.create {
+					p.release()
+				} else {
+					p.Put(g.resource)
+				}
+			}
+		}
+
+		return zero, ctx.Err()
+	}
+}
+
+// create calls the factory on a slot already reserved, releasing it if the factory fails.
+func (p *Pool[T]) create(ctx context.Context) (T, error) {
+	created := p.now()
+
+	resource, err := p.factory(ctx)
+	if err != nil {
+		p.release()
+
+		var zero T
+
+		return zero, err
+	}
+
+	if p.created != nil {
+		p.mu.Lock()
+		p.checkOutLocked(resource, created)
+		p.mu.Unlock()
+	}
+
+	return resource, nil
+}
+
+// Put return the resource to the pool, handing it off to a waiting Get, if any.
+// The resources beyond [WithMaxIdle], older than [WithMaxLifetime], or put back after Close,
+// are closed, see [WithOnClose].
+func (p *Pool[T]) Put(resource T) {
+	now := p.now()
+
+	p.mu.Lock()
+
+	created := p.checkInLocked(resource, now)
+
+	if p.maxLifetime > 0 && now.Sub(created) >= p.maxLifetime {
+		p.mu.Unlock()
+
+		p.Discard(resource)
+
+		return
+	}
+
+	if !p.closed && len(p.waiters) > 0 {
+		// the resource is checked out again, by the waiter.
+		p.checkOutLocked(resource, created)
+		p.grantLocked(grant[T]{resource: resource})
+		p.mu.Unlock()
+
+		return
+	}
+
+	if !p.closed && len(p.idle) < p.maxIdle {
+		p.idle = append(p.idle, idleResource[T]{resource: resource, created: created, since: now})
+		p.mu.Unlock()
+
+		return
+	}
+
+	p.mu.Unlock()
+
+	// the slot is released after the resource is closed, see CloseAndWait.
+	p.close(resource)
+	p.release()
+}
+
+// Discard closes a broken resource, see [WithOnClose], instead put it back to the pool,
+// releasing its slot of [WithMaxOpen].
+func (p *Pool[T]) Discard(resource T) {
+	if p.created != nil {
+		p.mu.Lock()
+		p.checkInLocked(resource, time.Time{})
+		p.mu.Unlock()
+	}
+
+	p.close(resource)
+
+	p.release()
+}
+
+// Close closes all idle resources, and makes the next calls to Get return [ErrClosed], including the ones waiting.
+// It also terminates the background culling, see [WithMaxIdleTime].
+// The resources checked out are closed when put back. It always returns nil, implementing [io.Closer].
+func (p *Pool[T]) Close() error {
+	p.culler.Stop()
+
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return nil
+	}
+
+	p.closed = true
+
+	idle := p.idle
+	p.idle = nil
+
+	for _, req := range p.waiters {
+		close(req)
+	}
+
+	p.waiters = nil
+	p.mu.Unlock()
+
+	for _, entry := range idle {
+		p.close(entry.resource)
+	}
+
+	p.mu.Lock()
+	p.closeOpenLocked(len(idle))
+	p.mu.Unlock()
+
+	return nil
+}
+
+// CloseAndWait closes the pool, like Close, and waits for the resources checked out to be put back,
+// or discarded, and closed, see [WithOnClose]. It returns the error of the context if it is done before,
+// the remaining resources are still closed when put back.
+func (p *Pool[T]) CloseAndWait(ctx context.Context) error {
+	_ = p.Close()
+
+	p.mu.Lock()
+
+	if p.numOpen == 0 {
+		p.mu.Unlock()
+
+		return nil
+	}
+
+	if p.drained == nil {
+		p.drained = make(chan struct{})
+	}
+
+	drained := p.drained
+
+	p.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cull closes the idle resources expired at now, see [WithMaxIdleTime] and [WithMaxLifetime].
+func (p *Pool[T]) cull(now time.Time) {
+	if p.maxIdleTime <= 0 && p.maxLifetime <= 0 {
+		return
+	}
+
+	var expired []T
+
+	p.mu.Lock()
+
+	kept := p.idle[:0]
+
+	for _, entry := range p.idle {
+		if p.expired(entry, now) {
+			expired = append(expired, entry.resource)
+
+			continue
+		}
+
+		kept = append(kept, entry)
+	}
+
+	for i := len(kept); i < len(p.idle); i++ {
+		p.idle[i] = idleResource[T]{} // do not retain the resources closed.
+	}
+
+	p.idle = kept
+	p.closeOpenLocked(len(expired))
+	p.mu.Unlock()
+
+	p.closeAll(expired)
+}
+
+func (p *Pool[T]) expired(entry idleResource[T], now time.Time) bool {
+	return (p.maxIdleTime > 0 && now.Sub(entry.since) >= p.maxIdleTime) ||
+		(p.maxLifetime > 0 && now.Sub(entry.created) >= p.maxLifetime)
+}
+
+// now returns the current time, only needed by [WithMaxIdleTime] and [WithMaxLifetime].
+func (p *Pool[T]) now() time.Time {
+	if p.maxIdleTime <= 0 && p.maxLifetime <= 0 {
+		return time.Time{}
+	}
+
+	return p.clock.Now()
+}
+
+// checkInLocked stops tracking a resource checked out, returning its creation time,
+// or now if the resource was not fetched from this pool, or the lifetime is not tracked.
+func (p *Pool[T]) checkInLocked(resource T, now time.Time) time.Time {
+	if p.created == nil {
+		return now
+	}
+
+	created, ok := p.created[resource]
+	if !ok {
+		return now
+	}
+
+	delete(p.created, resource)
+
+	return created
+}
+
+// checkOutLocked tracks the creation time of a resource checked out, for [WithMaxLifetime].
+func (p *Pool[T]) checkOutLocked(resource T, created time.Time) {
+	if p.created != nil {
+		p.created[resource] = created
+	}
+}
+
+// Stats is a point-in-time view of a [Pool].
+type Stats struct {
+	// Open is the number of open resources, idle or checked out.
+	Open int
+	// Idle is the number of idle resources.
+	Idle int
+	// Waiting is the number of calls to Get waiting for a slot of [WithMaxOpen].
+	Waiting int
+}
+
+// Stats returns the current state of the pool.
+func (p *Pool[T]) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return Stats{
+		Open:    p.numOpen,
+		Idle:    len(p.idle),
+		Waiting: len(p.waiters),
+	}
+}
+
+// release frees one slot of [WithMaxOpen], granting it to the first waiting Get, if any.
+func (p *Pool[T]) release() {
+	p.mu.Lock()
+
+	if !p.closed && len(p.waiters) > 0 {
+		// the slot is transferred to the waiter, numOpen does not change.
+		p.grantLocked(grant[T]{create: true})
+		p.mu.Unlock()
+
+		return
+	}
+
+	p.closeOpenLocked(1)
+	p.mu.Unlock()
+}
+
+// closeOpenLocked decrements the number of open resources, waking up CloseAndWait on the last one.
+func (p *Pool[T]) closeOpenLocked(n int) {
+	p.numOpen -= n
+
+	if p.numOpen == 0 && p.drained != nil {
+		close(p.drained)
+		p.drained = nil
+	}
+}
+
+// grantLocked sends the grant to the first waiting Get. It is sent under the lock, so a waiter whose
+// context is done either is still on the list, or finds the grant on its channel, see wait.
+// The channel has a buffer of one, so the send never blocks.
+func (p *Pool[T]) grantLocked(g grant[T]) {
+	req := p.waiters[0]
+	p.waiters[0] = nil
+	p.waiters = p.waiters[1:]
+
+	req <- g
+}
+
+// removeWaiterLocked returns false if the waiter is not on the list anymore: it was granted, or the pool was closed.
+func (p *Pool[T]) removeWaiterLocked(req chan grant[T]) bool {
+	for i, waiter := range p.waiters {
+		if waiter == req {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Pool[T]) closeAll(resources []T) {
+	for _, resource := range resources {
+		p.close(resource)
+	}
+}
+
+func (p *Pool[T]) close(resource T) {
+	if p.onClose != nil {
+		p.onClose(resource)
+	}
+}
@@ -0,0 +1,440 @@
+package respool_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/respool"
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+type session struct {
+	id     int64
+	closed bool
+}
+
+type sessionFactory struct {
+	created int64
+	mu      sync.Mutex
+	closed  []*session
+}
+
+func (f *sessionFactory) new(context.Context) (*session, error) {
+	return &session{id: atomic.AddInt64(&f.created, 1)}, nil
+}
+
+func (f *sessionFactory) close(s *session) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	s.closed = true
+	f.closed = append(f.closed, s)
+}
+
+func (f *sessionFactory) closedSessions() []*session {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*session(nil), f.closed...)
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new, respool.WithOnClose(factory.close))
+
+	ctx := context.Background()
+
+	s1, err := pool.Get(ctx)
+	require.NoError(t, err)
+
+	s2, err := pool.Get(ctx)
+	require.NoError(t, err)
+
+	s3, err := pool.Get(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, respool.Stats{Open: 3}, pool.Stats())
+
+	pool.Put(s1)
+	pool.Put(s2)
+	pool.Put(s3) // beyond DefaultMaxIdle, must be closed
+
+	assert.Equal(t, []*session{s3}, factory.closedSessions())
+	assert.Equal(t, respool.Stats{Open: 2, Idle: 2}, pool.Stats())
+
+	again, err := pool.Get(ctx)
+	require.NoError(t, err)
+	assert.Same(t, s2, again, "must reuse the most recently used")
+
+	pool.Discard(again)
+
+	assert.True(t, again.closed)
+	assert.Equal(t, respool.Stats{Open: 1, Idle: 1}, pool.Stats())
+
+	require.NoError(t, pool.Close())
+	require.NoError(t, pool.Close())
+
+	assert.True(t, s1.closed)
+	assert.Equal(t, respool.Stats{}, pool.Stats())
+
+	_, err = pool.Get(ctx)
+	require.ErrorIs(t, err, respool.ErrClosed)
+	require.ErrorIs(t, err, xpool.ErrClosed)
+}
+
+func TestPoolFactoryError(t *testing.T) {
+	t.Parallel()
+
+	errDial := errors.New("dial failed")
+
+	pool := respool.New(func(context.Context) (*session, error) {
+		return nil, errDial
+	}, respool.WithMaxOpen[*session](1))
+
+	for i := 0; i < 3; i++ {
+		_, err := pool.Get(context.Background())
+		require.ErrorIs(t, err, errDial, "must release the slot")
+	}
+
+	assert.Equal(t, respool.Stats{}, pool.Stats())
+}
+
+func TestWithMaxOpen(t *testing.T) {
+	t.Parallel()
+
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new, respool.WithMaxOpen[*session](1))
+
+	s, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = pool.Get(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	done := make(chan *session)
+
+	go func() {
+		got, err := pool.Get(context.Background())
+		assert.NoError(t, err)
+
+		done <- got
+	}()
+
+	for pool.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	pool.Put(s)
+
+	assert.Same(t, s, <-done, "must hand off the resource to the waiter")
+
+	go func() {
+		got, err := pool.Get(context.Background())
+		assert.NoError(t, err)
+
+		done <- got
+	}()
+
+	for pool.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	pool.Discard(s)
+
+	fresh := <-done
+	assert.NotSame(t, s, fresh, "must grant the slot to the waiter")
+	assert.Equal(t, int64(2), atomic.LoadInt64(&factory.created))
+	assert.Equal(t, respool.Stats{Open: 1}, pool.Stats())
+}
+
+func TestCloseWakesWaiters(t *testing.T) {
+	t.Parallel()
+
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new, respool.WithMaxOpen[*session](1), respool.WithOnClose(factory.close))
+
+	s, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	errs := make(chan error)
+
+	go func() {
+		_, err := pool.Get(context.Background())
+
+		errs <- err
+	}()
+
+	for pool.Stats().Waiting == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	require.NoError(t, pool.Close())
+	require.ErrorIs(t, <-errs, respool.ErrClosed)
+
+	pool.Put(s) // checked out during Close, must be closed
+
+	assert.True(t, s.closed)
+}
+
+func TestCloseAndWait(t *testing.T) {
+	t.Parallel()
+
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new, respool.WithOnClose(factory.close))
+
+	idle, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	checkedOut, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	pool.Put(idle)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, pool.CloseAndWait(ctx), context.DeadlineExceeded)
+	assert.Equal(t, []*session{idle}, factory.closedSessions())
+
+	_, err = pool.Get(context.Background())
+	require.ErrorIs(t, err, respool.ErrClosed)
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- pool.CloseAndWait(context.Background())
+	}()
+
+	pool.Put(checkedOut)
+
+	require.NoError(t, <-errs)
+	assert.Equal(t, []*session{idle, checkedOut}, factory.closedSessions())
+	assert.Equal(t, respool.Stats{}, pool.Stats())
+
+	require.NoError(t, pool.CloseAndWait(context.Background()))
+}
+
+func TestPoolConcurrent(t *testing.T) {
+	t.Parallel()
+
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new, respool.WithMaxOpen[*session](4), respool.WithMaxIdle[*session](4))
+
+	var (
+		wg       sync.WaitGroup
+		inFlight int64
+	)
+
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+
+				s, err := pool.Get(ctx)
+
+				cancel()
+
+				if err != nil {
+					continue
+				}
+
+				assert.LessOrEqual(t, atomic.AddInt64(&inFlight, 1), int64(4))
+				atomic.AddInt64(&inFlight, -1)
+
+				pool.Put(s)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	stats := pool.Stats()
+	assert.LessOrEqual(t, stats.Open, 4)
+	assert.Equal(t, stats.Open, stats.Idle, "all resources must be idle")
+	assert.Zero(t, stats.Waiting)
+}
+
+func TestPoolCancelDuringHandOff(t *testing.T) {
+	t.Parallel()
+
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new, respool.WithMaxOpen[*session](2), respool.WithMaxIdle[*session](1),
+		respool.WithOnClose(factory.close))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				// the waiters are cancelled while the resources are handed off, or their slots released.
+				ctx, cancel := context.WithTimeout(context.Background(), time.Duration(j%50)*time.Microsecond)
+
+				s, err := pool.Get(ctx)
+
+				cancel()
+
+				if err != nil {
+					continue
+				}
+
+				time.Sleep(time.Duration(j%3) * time.Microsecond)
+
+				if i%2 == 0 {
+					pool.Put(s)
+				} else {
+					pool.Discard(s)
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	stats := pool.Stats()
+	assert.Equal(t, stats.Open, stats.Idle, "must not leak a slot")
+	assert.Zero(t, stats.Waiting)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, pool.CloseAndWait(ctx), "must drain")
+	assert.Len(t, factory.closedSessions(), int(atomic.LoadInt64(&factory.created)), "must close all resources")
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "callback 'factory' must not be nil", func() {
+		respool.New[*session](nil)
+	})
+
+	assert.PanicsWithValue(t, "argument 'maxOpen' must be positive", func() {
+		respool.WithMaxOpen[*session](0)
+	})
+
+	assert.PanicsWithValue(t, "callback 'onClose' must not be nil", func() {
+		respool.WithOnClose[*session](nil)
+	})
+}
+
+func TestWithMaxIdleTime(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new,
+		respool.WithMaxIdleTime[*session](time.Minute),
+		respool.WithOnClose(factory.close),
+		respool.WithClock[*session](clock),
+	)
+	defer pool.Close()
+
+	ctx := context.Background()
+
+	s1, err := pool.Get(ctx)
+	require.NoError(t, err)
+
+	s2, err := pool.Get(ctx)
+	require.NoError(t, err)
+
+	pool.Put(s1)
+
+	clock.WaitTimers(1)
+	clock.Advance(30 * time.Second)
+
+	pool.Put(s2)
+
+	clock.WaitTimers(1)
+	clock.Advance(40 * time.Second) // s1 is idle for 70s, s2 for 40s
+
+	require.Eventually(t, func() bool {
+		return pool.Stats().Idle == 1
+	}, time.Second, time.Millisecond, "the culling must close s1")
+
+	assert.Equal(t, []*session{s1}, factory.closedSessions())
+
+	clock.Advance(30 * time.Second) // s2 is idle for 70s, culled on Get even before the background goroutine
+
+	got, err := pool.Get(ctx)
+	require.NoError(t, err)
+	assert.NotSame(t, s2, got)
+	assert.True(t, s2.closed)
+}
+
+func TestWithMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+	factory := new(sessionFactory)
+
+	pool := respool.New(factory.new,
+		respool.WithMaxLifetime[*session](time.Hour),
+		respool.WithOnClose(factory.close),
+		respool.WithClock[*session](clock),
+	)
+	defer pool.Close()
+
+	ctx := context.Background()
+
+	s, err := pool.Get(ctx)
+	require.NoError(t, err)
+
+	clock.Advance(30 * time.Minute)
+
+	pool.Put(s)
+
+	again, err := pool.Get(ctx)
+	require.NoError(t, err)
+	require.Same(t, s, again)
+
+	clock.Advance(45 * time.Minute)
+
+	pool.Put(again) // older than the lifetime, must be closed
+
+	assert.True(t, s.closed)
+	assert.Equal(t, respool.Stats{}, pool.Stats())
+}
+
+func TestCullOptionsInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'maxIdleTime' must be positive", func() {
+		respool.WithMaxIdleTime[*session](0)
+	})
+
+	assert.PanicsWithValue(t, "argument 'maxLifetime' must be positive", func() {
+		respool.WithMaxLifetime[*session](0)
+	})
+
+	assert.PanicsWithValue(t, "argument 'clock' must not be nil", func() {
+		respool.WithClock[*session](nil)
+	})
+}
@@ -0,0 +1,49 @@
+package ring
+
+import "sync"
+
+// duplicates tracks the idle objects, to detect the same object put back twice, see [WithDuplicateCheck].
+// A nil duplicates tracks nothing.
+type duplicates[T any] struct {
+	mu          sync.Mutex
+	idle        map[any]struct{}
+	onDuplicate func(object T)
+}
+
+func newDuplicates[T any](onDuplicate func(object T)) *duplicates[T] {
+	if onDuplicate == nil {
+		return nil
+	}
+
+	return &duplicates[T]{idle: make(map[any]struct{}), onDuplicate: onDuplicate}
+}
+
+// add returns false, after calling the callback, if the object is already idle.
+func (d *duplicates[T]) add(object T) bool {
+	if d == nil {
+		return true
+	}
+
+	d.mu.Lock()
+	_, found := d.idle[object]
+	d.idle[object] = struct{}{}
+	d.mu.Unlock()
+
+	if found {
+		d.onDuplicate(object)
+
+		return false
+	}
+
+	return true
+}
+
+func (d *duplicates[T]) remove(object T) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	delete(d.idle, object)
+	d.mu.Unlock()
+}
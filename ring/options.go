@@ -0,0 +1,151 @@
+package ring
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Option is a functional option to customize a ring [Pool].
+// It is parameterized on the same generic type T of the [Pool].
+type Option[T any] func(*options[T])
+
+type options[T any] struct {
+	onPutResetter func(object T) error
+	onDiscard     func(object T)
+	idleTimeout   time.Duration
+	minIdle       int
+	strictClose   bool
+	clock         xpool.Clock
+	warmInterval  time.Duration
+	warmTarget    int
+	onDuplicate   func(object T)
+}
+
+func buildOptions[T any](opts []Option[T]) *options[T] {
+	o := &options[T]{clock: xpool.SystemClock}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithResetter sets a resetter to be called before put the object back to the pool.
+// If the resetter fails, the object is discarded instead put it back to the pool.
+// Be careful, the resetter must be thread safe.
+// Will panic if onPutResetter is nil.
+func WithResetter[T any](onPutResetter func(object T) error) Option[T] {
+	if onPutResetter == nil {
+		panic("callback 'onPutResetter' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.onPutResetter = onPutResetter
+	}
+}
+
+// WithOnDiscard sets a callback to be called when the pool discards an object instead reuse it,
+// for instance when the pool is full or the resetter fails. Useful to release resources, like call Close.
+// Be careful, the callback must be thread safe.
+// Will panic if onDiscard is nil.
+func WithOnDiscard[T any](onDiscard func(object T)) Option[T] {
+	if onDiscard == nil {
+		panic("callback 'onDiscard' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.onDiscard = onDiscard
+	}
+}
+
+// WithIdleTimeout evicts the objects that are idle on the pool for longer than timeout,
+// calling the callback set via [WithOnDiscard], if any.
+// The eviction runs on a background goroutine, that must be terminated via [Pool.Stop].
+// Will panic if timeout is not positive.
+func WithIdleTimeout[T any](timeout time.Duration) Option[T] {
+	if timeout <= 0 {
+		panic("argument 'timeout' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.idleTimeout = timeout
+	}
+}
+
+// WithMinIdle creates minIdle objects on the pool construction, and keeps at least minIdle idle objects
+// after each eviction, see [WithIdleTimeout]: the evicted objects are replaced by fresh ones.
+// The idle objects of a ring [Pool] survive the garbage collection, so there is always warm capacity.
+// It is limited by the capacity of the pool.
+// Will panic if minIdle is not positive.
+func WithMinIdle[T any](minIdle int) Option[T] {
+	if minIdle <= 0 {
+		panic("argument 'minIdle' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.minIdle = minIdle
+	}
+}
+
+// WithStrictClose makes Get panic with [ErrClosed] after Close, instead create a new object,
+// to find the requests that race with the shutdown.
+func WithStrictClose[T any]() Option[T] {
+	return func(o *options[T]) {
+		o.strictClose = true
+	}
+}
+
+// WithWarmer tops the pool back up to targetIdle idle objects on the pool construction, and then
+// on each interval, on a background goroutine that must be terminated via [Pool.Stop].
+// Useful when the construction cost is tolerable on the background, but not on demand on the critical path.
+// It is limited by the capacity of the pool.
+// Will panic if interval or targetIdle are not positive.
+func WithWarmer[T any](interval time.Duration, targetIdle int) Option[T] {
+	if interval <= 0 {
+		panic("argument 'interval' must be positive")
+	}
+
+	if targetIdle <= 0 {
+		panic("argument 'targetIdle' must be positive")
+	}
+
+	return func(o *options[T]) {
+		o.warmInterval = interval
+		o.warmTarget = targetIdle
+	}
+}
+
+// WithClock sets the [xpool.Clock] used by the idle eviction, see [WithIdleTimeout],
+// like a fake clock on tests. By default, it uses [xpool.SystemClock].
+// Will panic if clock is nil.
+func WithClock[T any](clock xpool.Clock) Option[T] {
+	if clock == nil {
+		panic("argument 'clock' must not be nil")
+	}
+
+	return func(o *options[T]) {
+		o.clock = clock
+	}
+}
+
+// WithDuplicateCheck is a debug option that detects the same object put back twice while it is idle,
+// a symptom of a double Put, calling onDuplicate immediately, instead waiting for the corruption
+// when two callers get the same object. The duplicate is not stored. The callback may panic.
+// It tracks the idle objects on a map, so it is slower, and T must be comparable, like a pointer.
+// Will panic if onDuplicate is nil, or if T is not comparable.
+func WithDuplicateCheck[T any](onDuplicate func(object T)) Option[T] {
+	if onDuplicate == nil {
+		panic("callback 'onDuplicate' must not be nil")
+	}
+
+	if !reflect.TypeOf((*T)(nil)).Elem().Comparable() {
+		panic("type parameter 'T' must be comparable")
+	}
+
+	return func(o *options[T]) {
+		o.onDuplicate = onDuplicate
+	}
+}
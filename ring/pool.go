@@ -0,0 +1,330 @@
+// Package ring offers a fixed-capacity object pool backed by a lock-free stack,
+// as an alternative to [sync.Pool] when the idle objects must survive the garbage collection
+// and the capacity must be bounded.
+//
+//	pool := ring.New(1024, func() *Parser {
+//	  return NewParser()
+//	}, ring.WithResetter(func(p *Parser) error {
+//	  return p.Reset()
+//	}))
+//
+//	parser := pool.Get()
+//	defer pool.Put(parser)
+//
+// Get and Put only need a CAS on success, without locks or channels.
+// The stack is LIFO: Get returns the most recently used object, still warm on the CPU caches,
+// while the surplus objects stay idle on the bottom, where they can be evicted, see [WithIdleTimeout].
+// When the stack is empty Get calls the constructor, and when the stack is full Put discards the object.
+//
+// Some options, like [WithIdleTimeout] and [WithWarmer], start a background goroutine that must be terminated via [Pool.Stop],
+// or via [Pool.Close] on the graceful shutdown.
+package ring
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/internal/maintenance"
+)
+
+var (
+	_ xpool.Pool[any]          = (*Pool[any])(nil)
+	_ xpool.ContextGetter[any] = (*Pool[any])(nil)
+)
+
+// ErrClosed is returned by GetContext after Close. It is the same error of [xpool.ErrClosed].
+var ErrClosed = xpool.ErrClosed
+
+// Pool is a fixed-capacity object pool backed by a lock-free stack.
+type Pool[T any] struct {
+	stack         *stack[idle[T]]
+	ctor          func() T
+	onPutResetter func(object T) error
+	onDiscard     func(object T)
+	idleTimeout   time.Duration
+	minIdle       int
+	maintenance   *maintenance.Maintenance
+	warmer        *maintenance.Maintenance
+	warmTarget    int
+	duplicates    *duplicates[T]
+	closed        uint32
+	strictClose   bool
+	clock         xpool.Clock
+}
+
+// idle is an object stored on the stack, with the time it was put back if needed.
+type idle[T any] struct {
+	object T
+	since  time.Time
+}
+
+// New returns a [Pool] that retains up to capacity idle objects, rounded up to a power of two.
+// Receives the constructor of the type T.
+// The behavior can be customized via [Option].
+// Will panic if capacity is not positive.
+func New[T any](capacity int, ctor func() T, opts ...Option[T]) *Pool[T] {
+	if capacity <= 0 {
+		panic("argument 'capacity' must be positive")
+	}
+
+	o := buildOptions(opts)
+
+	p := &Pool[T]{
+		stack:         newStack[idle[T]](capacity),
+		ctor:          ctor,
+		onPutResetter: o.onPutResetter,
+		onDiscard:     o.onDiscard,
+		idleTimeout:   o.idleTimeout,
+		minIdle:       o.minIdle,
+		warmTarget:    o.warmTarget,
+		duplicates:    newDuplicates(o.onDuplicate),
+		strictClose:   o.strictClose,
+		clock:         o.clock,
+	}
+
+	if p.minIdle > p.Cap() {
+		p.minIdle = p.Cap()
+	}
+
+	if p.warmTarget > p.Cap() {
+		p.warmTarget = p.Cap()
+	}
+
+	p.refill(p.clock.Now())
+	p.warm(p.clock.Now())
+
+	if p.idleTimeout > 0 {
+		p.maintenance = maintenance.Start(p.clock, p.idleTimeout/2, p.evict)
+	}
+
+	if o.warmInterval > 0 {
+		p.warmer = maintenance.Start(p.clock, o.warmInterval, p.warm)
+	}
+
+	return p
+}
+
+// Get fetch the most recently used idle object from the stack. If the stack is empty, will create another object.
+// After Close, it creates a new object, or panics with [ErrClosed] if [WithStrictClose] is used.
+func (p *Pool[T]) Get() T {
+	if p.strictClose && atomic.LoadUint32(&p.closed) != 0 {
+		panic(ErrClosed)
+	}
+
+	if entry, ok := p.stack.pop(); ok {
+		p.duplicates.remove(entry.object)
+
+		return entry.object
+	}
+
+	return p.ctor()
+}
+
+// GetContext fetch the most recently used idle object from the stack, like Get, but it returns [ErrClosed] after Close,
+// or an error if the context is done.
+func (p *Pool[T]) GetContext(ctx context.Context) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if atomic.LoadUint32(&p.closed) != 0 {
+		return zero, ErrClosed
+	}
+
+	if entry, ok := p.stack.pop(); ok {
+		p.duplicates.remove(entry.object)
+
+		return entry.object, nil
+	}
+
+	return p.ctor(), nil
+}
+
+// Put return the object to the top of the stack, or discard it if the stack is full or closed.
+func (p *Pool[T]) Put(object T) {
+	if atomic.LoadUint32(&p.closed) != 0 {
+		p.discard(object)
+
+		return
+	}
+
+	if !p.duplicates.add(object) {
+		return // the object is already idle.
+	}
+
+	if p.onPutResetter != nil {
+		if err := p.onPutResetter(object); err != nil {
+			p.drop(object)
+
+			return
+		}
+	}
+
+	entry := idle[T]{object: object}
+	if p.idleTimeout > 0 {
+		entry.since = p.clock.Now()
+	}
+
+	if !p.push(entry) {
+		p.drop(object)
+
+		return
+	}
+
+	// the pool may be closed while we were pushing the object.
+	if atomic.LoadUint32(&p.closed) != 0 {
+		p.drain()
+	}
+}
+
+// Stop terminates the background goroutines started by some options, like [WithIdleTimeout] and [WithWarmer].
+// The pool can still be used after Stop, without the background tasks. It is safe to call it several times.
+func (p *Pool[T]) Stop() {
+	p.maintenance.Stop()
+	p.warmer.Stop()
+}
+
+// Close stops the background goroutines, like Stop, and discards all idle objects,
+// calling the callback set via [WithOnDiscard], if any.
+// After Close, Put discards the object, Get creates a new object, see [WithStrictClose],
+// and GetContext returns [ErrClosed].
+// It is safe to call it several times.
+func (p *Pool[T]) Close() error {
+	atomic.StoreUint32(&p.closed, 1)
+
+	p.Stop()
+	p.drain()
+
+	return nil
+}
+
+func (p *Pool[T]) drain() {
+	for {
+		entry, ok := p.stack.pop()
+		if !ok {
+			return
+		}
+
+		p.drop(entry.object)
+	}
+}
+
+// evict discards the objects idle for longer than the timeout, and refill the pool.
+// The expired objects are on the bottom of the stack, so the idle objects are taken out and the
+// survivors are pushed back, from the oldest to the newest. It is skipped when no object is expired.
+func (p *Pool[T]) evict(now time.Time) {
+	defer p.refill(now)
+
+	deadline := now.Add(-p.idleTimeout).UnixNano()
+
+	expired := func(since int64) bool {
+		return since <= deadline
+	}
+
+	if !p.stack.hasStamp(expired) {
+		return
+	}
+
+	entries := p.popAll()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+
+		if expired(entry.since.UnixNano()) || !p.push(entry) {
+			p.drop(entry.object)
+		}
+	}
+}
+
+// refill creates objects until the pool has the minimum number of idle objects, see [WithMinIdle].
+func (p *Pool[T]) refill(now time.Time) {
+	p.fill(now, p.minIdle)
+}
+
+// warm creates objects until the pool has the target number of idle objects, see [WithWarmer].
+func (p *Pool[T]) warm(now time.Time) {
+	p.fill(now, p.warmTarget)
+}
+
+func (p *Pool[T]) fill(now time.Time, target int) {
+	for p.stack.len() < target && atomic.LoadUint32(&p.closed) == 0 {
+		object := p.ctor()
+		p.duplicates.add(object)
+
+		if !p.push(idle[T]{object: object, since: now}) {
+			p.drop(object)
+
+			return
+		}
+	}
+}
+
+// Cap returns the maximum number of idle objects.
+func (p *Pool[T]) Cap() int {
+	return len(p.stack.nodes)
+}
+
+// Len returns an approximation of the number of idle objects.
+func (p *Pool[T]) Len() int {
+	return p.stack.len()
+}
+
+// Inspect calls fn for each idle object, from the oldest to the newest, and put it back to the stack.
+// It is intended for tests and drain logic: it is not atomic, so an object fetched or put back
+// concurrently may be missed, and the objects are not available to Get while fn runs.
+func (p *Pool[T]) Inspect(fn func(object T)) {
+	entries := p.popAll()
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+
+		fn(entry.object)
+
+		if !p.push(entry) {
+			p.drop(entry.object)
+		}
+	}
+}
+
+// popAll takes out the idle objects, from the newest to the oldest.
+// It is bounded by the current length, so it ends even if other goroutines keep putting objects back.
+func (p *Pool[T]) popAll() []idle[T] {
+	entries := make([]idle[T], 0, p.stack.len())
+
+	for n := cap(entries); n > 0; n-- {
+		entry, ok := p.stack.pop()
+		if !ok {
+			break
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// push stores the entry on the stack, stamped with the time it became idle, see evict.
+func (p *Pool[T]) push(entry idle[T]) bool {
+	var stamp int64
+	if p.idleTimeout > 0 {
+		stamp = entry.since.UnixNano()
+	}
+
+	return p.stack.push(entry, stamp)
+}
+
+func (p *Pool[T]) discard(object T) {
+	if p.onDiscard != nil {
+		p.onDiscard(object)
+	}
+}
+
+// drop discards an object that is not idle anymore.
+func (p *Pool[T]) drop(object T) {
+	p.duplicates.remove(object)
+	p.discard(object)
+}
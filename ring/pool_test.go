@@ -0,0 +1,528 @@
+package ring_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/ring"
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+func newBuffer() *bytes.Buffer {
+	return new(bytes.Buffer)
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*bytes.Buffer
+
+	pool := ring.New(3, newBuffer, ring.WithOnDiscard(func(b *bytes.Buffer) {
+		discarded = append(discarded, b)
+	}))
+
+	require.Equal(t, 4, pool.Cap(), "must round up to a power of two")
+	require.Zero(t, pool.Len())
+
+	buffers := make([]*bytes.Buffer, 5)
+	for i := range buffers {
+		buffers[i] = pool.Get()
+	}
+
+	for _, b := range buffers {
+		pool.Put(b)
+	}
+
+	assert.Equal(t, 4, pool.Len())
+	assert.Equal(t, []*bytes.Buffer{buffers[4]}, discarded, "must discard when full")
+
+	for i := 3; i >= 0; i-- {
+		assert.Same(t, buffers[i], pool.Get(), "must be LIFO")
+	}
+
+	assert.Zero(t, pool.Len())
+}
+
+func TestWithResetter(t *testing.T) {
+	t.Parallel()
+
+	var discards int
+
+	pool := ring.New(4, newBuffer, ring.WithResetter(func(b *bytes.Buffer) error {
+		if b.Len() > 3 {
+			return errors.New("too big")
+		}
+
+		b.Reset()
+
+		return nil
+	}), ring.WithOnDiscard(func(*bytes.Buffer) {
+		discards++
+	}))
+
+	small := pool.Get()
+	small.WriteString("foo")
+	pool.Put(small)
+
+	big := pool.Get()
+	require.Same(t, small, big)
+	assert.Zero(t, big.Len(), "must reset")
+
+	big.WriteString("foobar")
+	pool.Put(big)
+
+	assert.Equal(t, 1, discards)
+	assert.Zero(t, pool.Len())
+}
+
+func TestPoolConcurrency(t *testing.T) {
+	t.Parallel()
+
+	var duplicates int64
+
+	pool := ring.New(64, func() *int64 {
+		return new(int64)
+	})
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 10000; i++ {
+				object := pool.Get()
+
+				// must not be handed out twice at the same time.
+				if !atomic.CompareAndSwapInt64(object, 0, 1) {
+					atomic.AddInt64(&duplicates, 1)
+				}
+
+				atomic.StoreInt64(object, 0)
+				pool.Put(object)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Zero(t, atomic.LoadInt64(&duplicates), "object handed out twice")
+}
+
+func TestWithIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	var discards int64
+
+	pool := ring.New(4, newBuffer, ring.WithIdleTimeout[*bytes.Buffer](20*time.Millisecond),
+		ring.WithOnDiscard(func(*bytes.Buffer) {
+			atomic.AddInt64(&discards, 1)
+		}))
+	defer pool.Stop()
+
+	pool.Put(pool.Get())
+	pool.Put(pool.Get())
+	require.Equal(t, 1, pool.Len())
+
+	assert.Eventually(t, func() bool {
+		return pool.Len() == 0
+	}, time.Second, 5*time.Millisecond, "must evict the idle object")
+
+	assert.Equal(t, int64(1), atomic.LoadInt64(&discards))
+
+	pool.Stop()
+	pool.Stop() // idempotent
+
+	pool.Put(pool.Get())
+	assert.Equal(t, 1, pool.Len(), "must work after stop")
+}
+
+func TestClose(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*bytes.Buffer
+
+	pool := ring.New(4, newBuffer, ring.WithIdleTimeout[*bytes.Buffer](time.Minute),
+		ring.WithOnDiscard(func(b *bytes.Buffer) {
+			discarded = append(discarded, b)
+		}))
+
+	first, second := pool.Get(), pool.Get()
+	pool.Put(first)
+
+	require.NoError(t, pool.Close())
+	require.NoError(t, pool.Close()) // idempotent
+
+	assert.Zero(t, pool.Len())
+	assert.Equal(t, []*bytes.Buffer{first}, discarded, "must drain the idle objects")
+
+	pool.Put(second)
+	assert.Zero(t, pool.Len())
+	assert.Equal(t, []*bytes.Buffer{first, second}, discarded, "must discard after close")
+
+	assert.NotNil(t, pool.Get(), "plain Get must create the object")
+
+	_, err := pool.GetContext(context.Background())
+	require.ErrorIs(t, err, ring.ErrClosed)
+	require.ErrorIs(t, err, xpool.ErrClosed)
+
+	_, err = xpool.GetContext[*bytes.Buffer](context.Background(), pool)
+	require.ErrorIs(t, err, xpool.ErrClosed)
+}
+
+func TestWithStrictClose(t *testing.T) {
+	t.Parallel()
+
+	pool := ring.New(4, func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, ring.WithStrictClose[*bytes.Buffer]())
+
+	assert.NotNil(t, pool.Get())
+
+	require.NoError(t, pool.Close())
+
+	assert.PanicsWithValue(t, xpool.ErrClosed, func() {
+		pool.Get()
+	}, "must panic after close")
+}
+
+func TestWithMinIdle(t *testing.T) {
+	t.Parallel()
+
+	var created int64
+
+	pool := ring.New(4, func() *bytes.Buffer {
+		atomic.AddInt64(&created, 1)
+
+		return new(bytes.Buffer)
+	}, ring.WithMinIdle[*bytes.Buffer](2), ring.WithIdleTimeout[*bytes.Buffer](20*time.Millisecond))
+	defer pool.Stop()
+
+	require.Equal(t, 2, pool.Len(), "must create the objects on construction")
+
+	first := pool.Get()
+	require.Equal(t, int64(2), atomic.LoadInt64(&created))
+
+	assert.Eventually(t, func() bool {
+		return atomic.LoadInt64(&created) >= 4
+	}, time.Second, 5*time.Millisecond, "must replace the evicted objects")
+
+	pool.Put(first)
+	assert.GreaterOrEqual(t, pool.Len(), 2)
+}
+
+func TestWithMinIdleCapped(t *testing.T) {
+	t.Parallel()
+
+	pool := ring.New(2, newBuffer, ring.WithMinIdle[*bytes.Buffer](10))
+
+	assert.Equal(t, 2, pool.Len(), "must be limited by the capacity")
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		ring.New(0, newBuffer)
+	}, "must panic")
+
+	assert.Panics(t, func() {
+		ring.WithResetter[*bytes.Buffer](nil)
+	}, "must panic")
+
+	assert.Panics(t, func() {
+		ring.WithOnDiscard[*bytes.Buffer](nil)
+	}, "must panic")
+
+	assert.Panics(t, func() {
+		ring.WithIdleTimeout[*bytes.Buffer](0)
+	}, "must panic")
+
+	assert.Panics(t, func() {
+		ring.WithMinIdle[*bytes.Buffer](0)
+	}, "must panic")
+}
+
+func BenchmarkRing(b *testing.B) {
+	benchmarkPool(b, ring.New(1024, newBuffer))
+}
+
+func BenchmarkSyncPool(b *testing.B) {
+	benchmarkPool(b, xpool.New(newBuffer))
+}
+
+func benchmarkPool(b *testing.B, pool xpool.Pool[*bytes.Buffer]) {
+	b.Helper()
+
+	b.Run("serial", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			buf := pool.Get()
+			buf.WriteByte('x')
+			buf.Reset()
+			pool.Put(buf)
+		}
+	})
+
+	b.Run("parallel", func(b *testing.B) {
+		b.ReportAllocs()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				buf := pool.Get()
+				buf.WriteByte('x')
+				buf.Reset()
+				pool.Put(buf)
+			}
+		})
+	})
+}
+
+func TestInspect(t *testing.T) {
+	t.Parallel()
+
+	pool := ring.New(4, func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	first, second := pool.Get(), pool.Get()
+	pool.Put(first)
+	pool.Put(second)
+
+	var seen []*bytes.Buffer
+
+	pool.Inspect(func(b *bytes.Buffer) {
+		seen = append(seen, b)
+	})
+
+	require.Len(t, seen, 2)
+	assert.Same(t, first, seen[0], "must be from the oldest to the newest")
+	assert.Same(t, second, seen[1])
+
+	assert.Equal(t, 2, pool.Len(), "must keep the idle objects")
+	assert.Same(t, second, pool.Get(), "must keep the order")
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+
+	var discarded int64
+
+	pool := ring.New(4, func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	},
+		ring.WithIdleTimeout[*bytes.Buffer](time.Minute),
+		ring.WithClock[*bytes.Buffer](clock),
+		ring.WithOnDiscard(func(*bytes.Buffer) {
+			atomic.AddInt64(&discarded, 1)
+		}),
+	)
+	defer pool.Stop()
+
+	pool.Put(new(bytes.Buffer))
+
+	clock.WaitTimers(1)
+	clock.Advance(30 * time.Second)
+	clock.WaitTimers(1) // the eviction ran, and the timer was reset
+
+	assert.Equal(t, 1, pool.Len(), "must keep the object idle for less than the timeout")
+
+	clock.Advance(30 * time.Second)
+	clock.WaitTimers(1)
+
+	assert.Zero(t, pool.Len(), "must evict the object idle for the timeout")
+	assert.Equal(t, int64(1), atomic.LoadInt64(&discarded))
+}
+
+func TestWithIdleTimeoutMixedAges(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+
+	var (
+		mu        sync.Mutex
+		discarded []*bytes.Buffer
+	)
+
+	pool := ring.New(4, func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	},
+		ring.WithIdleTimeout[*bytes.Buffer](time.Minute), // the eviction runs every 30s
+		ring.WithClock[*bytes.Buffer](clock),
+		ring.WithOnDiscard(func(b *bytes.Buffer) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			discarded = append(discarded, b)
+		}),
+	)
+	defer pool.Stop()
+
+	older, newer, newest := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+
+	clock.WaitTimers(1)
+
+	pool.Put(older) // idle since 0s
+
+	clock.Advance(10 * time.Second)
+	pool.Put(newer) // idle since 10s
+
+	clock.Advance(20 * time.Second)
+	clock.WaitTimers(1) // at 30s nothing is expired, the stack must keep its order
+
+	clock.Advance(15 * time.Second)
+	pool.Put(newest) // idle since 45s
+
+	clock.Advance(15 * time.Second)
+	clock.WaitTimers(1) // at 60s only the older one is expired
+
+	mu.Lock()
+	assert.Equal(t, []*bytes.Buffer{older}, discarded)
+	mu.Unlock()
+
+	clock.Advance(30 * time.Second)
+	clock.WaitTimers(1) // at 90s the newer one is expired
+
+	mu.Lock()
+	assert.Equal(t, []*bytes.Buffer{older, newer}, discarded)
+	mu.Unlock()
+
+	require.Equal(t, 1, pool.Len())
+	assert.Same(t, newest, pool.Get())
+}
+
+func TestWithIdleTimeoutSteadyLoad(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+
+	var (
+		mu        sync.Mutex
+		discarded []*bytes.Buffer
+	)
+
+	pool := ring.New(4, func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	},
+		ring.WithIdleTimeout[*bytes.Buffer](time.Minute), // the eviction runs every 30s
+		ring.WithClock[*bytes.Buffer](clock),
+		ring.WithOnDiscard(func(b *bytes.Buffer) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			discarded = append(discarded, b)
+		}),
+	)
+	defer pool.Stop()
+
+	first, second, hot := new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+
+	clock.WaitTimers(1)
+
+	pool.Put(first) // the surplus after a burst, idle since 0s
+	pool.Put(second)
+	pool.Put(hot)
+
+	cycle := func() {
+		object := pool.Get()
+		assert.Same(t, hot, object, "must reuse the most recently used object")
+		pool.Put(object)
+	}
+
+	clock.Advance(20 * time.Second)
+	cycle()
+
+	clock.Advance(10 * time.Second)
+	clock.WaitTimers(1) // at 30s nothing is expired
+
+	clock.Advance(20 * time.Second)
+	cycle() // idle since 50s
+
+	clock.Advance(10 * time.Second)
+	clock.WaitTimers(1) // at 60s the surplus is expired, even under a steady load
+
+	mu.Lock()
+	assert.Equal(t, []*bytes.Buffer{first, second}, discarded)
+	mu.Unlock()
+
+	require.Equal(t, 1, pool.Len())
+	assert.Same(t, hot, pool.Get())
+}
+
+func TestWithWarmer(t *testing.T) {
+	t.Parallel()
+
+	clock := xpooltest.NewClock(time.Now())
+
+	var created int64
+
+	pool := ring.New(8, func() *bytes.Buffer {
+		atomic.AddInt64(&created, 1)
+
+		return new(bytes.Buffer)
+	}, ring.WithWarmer[*bytes.Buffer](time.Second, 3), ring.WithClock[*bytes.Buffer](clock))
+	defer pool.Stop()
+
+	assert.Equal(t, 3, pool.Len(), "must warm on the construction")
+
+	pool.Get()
+	pool.Get()
+	assert.Equal(t, 1, pool.Len())
+
+	clock.WaitTimers(1)
+	clock.Advance(time.Second)
+	clock.WaitTimers(1) // the warmer ran, and the timer was reset
+
+	assert.Equal(t, 3, pool.Len(), "must top up the pool")
+	assert.Equal(t, int64(5), atomic.LoadInt64(&created))
+
+	assert.Panics(t, func() {
+		ring.WithWarmer[*bytes.Buffer](0, 1)
+	}, "must panic if interval is not positive")
+
+	assert.Panics(t, func() {
+		ring.WithWarmer[*bytes.Buffer](time.Second, 0)
+	}, "must panic if targetIdle is not positive")
+}
+
+func TestWithDuplicateCheck(t *testing.T) {
+	t.Parallel()
+
+	var duplicates []*bytes.Buffer
+
+	pool := ring.New(4, func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, ring.WithDuplicateCheck(func(b *bytes.Buffer) {
+		duplicates = append(duplicates, b)
+	}), ring.WithMinIdle[*bytes.Buffer](1))
+
+	object := pool.Get() // from the min idle
+	pool.Put(object)
+	pool.Put(object)
+
+	assert.Equal(t, []*bytes.Buffer{object}, duplicates)
+	assert.Equal(t, 1, pool.Len(), "must not store the duplicate")
+
+	assert.Same(t, object, pool.Get())
+
+	pool.Put(object) // not idle anymore
+	assert.Len(t, duplicates, 1)
+
+	assert.Panics(t, func() {
+		ring.WithDuplicateCheck(func([]byte) {})
+	}, "must panic if T is not comparable")
+}
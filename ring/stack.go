@@ -0,0 +1,144 @@
+package ring
+
+import "sync/atomic"
+
+const cacheLinePad = 64
+
+// stack is a bounded multi-producer multi-consumer LIFO stack, so the most recently used objects,
+// still warm on the CPU caches, are reused first, while the least recently used ones sink to the bottom,
+// where they age out, see [WithIdleTimeout].
+//
+// The nodes are preallocated and linked by index on two Treiber stacks: the full nodes and the free ones.
+// Each head packs the index of the top node, plus one, on the low 32 bits and a tag on the high 32 bits,
+// incremented on each change, so a CAS never succeeds on a head popped and pushed back meanwhile (the ABA problem).
+type stack[T any] struct {
+	_      [cacheLinePad]byte
+	full   uint64
+	_      [cacheLinePad]byte
+	free   uint64
+	_      [cacheLinePad]byte
+	count  int64
+	nodes  []node[T]
+	stamps []int64 // apart from the nodes, so the atomic operations are aligned on 32 bits platforms.
+}
+
+type node[T any] struct {
+	next  uint32 // the index of the next node, plus one, or zero on the bottom of the stack.
+	value T
+}
+
+// newStack returns a stack with the capacity rounded up to a power of two.
+func newStack[T any](capacity int) *stack[T] {
+	size := 1
+	for size < capacity {
+		size <<= 1
+	}
+
+	s := &stack[T]{
+		nodes:  make([]node[T], size),
+		stamps: make([]int64, size),
+	}
+
+	// all nodes start on the free stack, the node i on top of the node i-1.
+	for i := range s.nodes {
+		s.nodes[i].next = uint32(i)
+	}
+
+	s.free = uint64(size)
+
+	return s
+}
+
+// push returns false if the stack is full. The stamp is a key of the value, like a timestamp, see hasStamp.
+func (s *stack[T]) push(value T, stamp int64) bool {
+	i, ok := s.popNode(&s.free)
+	if !ok {
+		return false
+	}
+
+	s.nodes[i].value = value
+	atomic.StoreInt64(&s.stamps[i], stamp)
+
+	s.pushNode(&s.full, i)
+	atomic.AddInt64(&s.count, 1)
+
+	return true
+}
+
+// pop returns the value on the top of the stack, the most recently pushed, or false if the stack is empty.
+func (s *stack[T]) pop() (T, bool) {
+	var zero T
+
+	i, ok := s.popNode(&s.full)
+	if !ok {
+		return zero, false
+	}
+
+	value := s.nodes[i].value
+	s.nodes[i].value = zero // do not retain the object
+
+	s.pushNode(&s.free, i)
+	atomic.AddInt64(&s.count, -1)
+
+	return value, true
+}
+
+// hasStamp walks the stack from the top to the bottom, and returns true if cond returns true for some stamp.
+// It does not claim the nodes, so it is only a hint: a value consumed concurrently may be missed or reported.
+func (s *stack[T]) hasStamp(cond func(stamp int64) bool) bool {
+	top := uint32(atomic.LoadUint64(&s.full))
+
+	// bounded, since the nodes may be relinked concurrently.
+	for n := 0; top != 0 && n < len(s.nodes); n++ {
+		if cond(atomic.LoadInt64(&s.stamps[top-1])) {
+			return true
+		}
+
+		top = atomic.LoadUint32(&s.nodes[top-1].next)
+	}
+
+	return false
+}
+
+func (s *stack[T]) popNode(head *uint64) (uint32, bool) {
+	for {
+		h := atomic.LoadUint64(head)
+
+		top := uint32(h)
+		if top == 0 {
+			return 0, false
+		}
+
+		next := atomic.LoadUint32(&s.nodes[top-1].next)
+
+		if atomic.CompareAndSwapUint64(head, h, (h>>32+1)<<32|uint64(next)) {
+			return top - 1, true
+		}
+	}
+}
+
+func (s *stack[T]) pushNode(head *uint64, i uint32) {
+	for {
+		h := atomic.LoadUint64(head)
+
+		atomic.StoreUint32(&s.nodes[i].next, uint32(h))
+
+		if atomic.CompareAndSwapUint64(head, h, (h>>32+1)<<32|uint64(i+1)) {
+			return
+		}
+	}
+}
+
+// len returns an approximation of the number of values in the stack.
+func (s *stack[T]) len() int {
+	n := int(atomic.LoadInt64(&s.count))
+
+	switch {
+	case n < 0:
+		return 0
+	case n > len(s.nodes):
+		return len(s.nodes)
+	default:
+		return n
+	}
+}
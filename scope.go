@@ -0,0 +1,215 @@
+package xpool
+
+import (
+	"context"
+	"sync"
+)
+
+// Scope is a request-scoped free list on top of a parent [Pool].
+// Get prefers the objects put back to the scope, and Close returns them to the parent pool,
+// so objects can be reused during a request without contention with other requests.
+//
+// The objects put back to the scope are reset like the parent pool does on Put, for the pools that reset
+// the objects, like [NewWithResetter], [NewWithAutoReset], [DirectPool] and [PtrPool], so Get returns clean objects.
+// If the reset fails, the object is discarded by the parent pool, see [Discard].
+// The objects are reset again when Close put them back to the parent pool.
+type Scope[T any] struct {
+	parent     Pool[T]
+	mu         sync.Mutex
+	free       []T
+	closed     bool
+	scratch    T
+	hasScratch bool
+}
+
+var _ Pool[any] = (*Scope[any])(nil)
+
+type scopeKey[T any] struct{}
+
+// putResetter is implemented by the pools that reset the objects on Put, and by the pools that wrap them,
+// so a [Scope] can reset the objects put back to it.
+type putResetter[T any] interface {
+	resetOnPut(object T) error
+}
+
+// resetOnPut resets the object like the pool does on Put, without storing it.
+// It returns nil if the pool does not reset the objects.
+func resetOnPut[T any](pool Pool[T], object T) error {
+	if resetter, ok := pool.(putResetter[T]); ok {
+		return resetter.resetOnPut(object)
+	}
+
+	return nil
+}
+
+// NewScope returns a [Scope] for the parent pool and a copy of ctx that carries it,
+// see [ScopeFromContext]. The scope must be closed at the end of the request.
+// Will panic if parent is nil.
+func NewScope[T any](ctx context.Context, parent Pool[T]) (context.Context, *Scope[T]) {
+	if parent == nil {
+		panic("argument 'parent' must not be nil")
+	}
+
+	scope := &Scope[T]{parent: parent}
+
+	return context.WithValue(ctx, scopeKey[T]{}, scope), scope
+}
+
+// ScopeFromContext returns the [Scope] of type T carried by ctx, if any.
+func ScopeFromContext[T any](ctx context.Context) (*Scope[T], bool) {
+	scope, ok := ctx.Value(scopeKey[T]{}).(*Scope[T])
+
+	return scope, ok
+}
+
+// FromContext returns the scratch object of type T cached on the [Scope] carried by ctx,
+// fetched from the scope on the first call, so deeply nested calls can share it without
+// threading it through every signature. The object is put back when the scope is closed,
+// and release does nothing.
+// If ctx does not carry a scope, the object is fetched from pool and release put it back.
+//
+// Be careful, all calls with the same scope share the same object, including other goroutines.
+func FromContext[T any](ctx context.Context, pool Pool[T]) (object T, release func()) {
+	scope, ok := ScopeFromContext[T](ctx)
+	if !ok {
+		object = pool.Get()
+
+		return object, func() { pool.Put(object) }
+	}
+
+	return scope.sharedScratch(), func() {}
+}
+
+func (s *Scope[T]) sharedScratch() T {
+	s.mu.Lock()
+
+	if s.hasScratch {
+		defer s.mu.Unlock()
+
+		return s.scratch
+	}
+
+	s.mu.Unlock()
+
+	// the parent pool may block, so it must be called without the lock.
+	object := s.Get()
+
+	s.mu.Lock()
+
+	if s.hasScratch {
+		// another goroutine was faster.
+		scratch := s.scratch
+		s.mu.Unlock()
+
+		s.Put(object)
+
+		return scratch
+	}
+
+	if !s.closed {
+		s.scratch, s.hasScratch = object, true
+	}
+
+	s.mu.Unlock()
+
+	return object
+}
+
+// Get fetch one item from the scope, or from the parent pool if the scope is empty.
+func (s *Scope[T]) Get() T {
+	s.mu.Lock()
+
+	if n := len(s.free); n > 0 {
+		object := s.free[n-1]
+
+		var zero T
+		s.free[n-1] = zero
+		s.free = s.free[:n-1]
+
+		s.mu.Unlock()
+
+		return object
+	}
+
+	s.mu.Unlock()
+
+	return s.parent.Get()
+}
+
+// Put resets the object and return it to the scope, or to the parent pool if the scope is closed.
+// If the reset fails, the object is discarded by the parent pool.
+func (s *Scope[T]) Put(object T) {
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+		s.parent.Put(object)
+
+		return
+	}
+
+	s.mu.Unlock()
+
+	// the resetter may be slow, so it must be called without the lock.
+	if err := resetOnPut(s.parent, object); err != nil {
+		Discard(s.parent, object)
+
+		return
+	}
+
+	s.mu.Lock()
+
+	if !s.closed {
+		s.free = append(s.free, object)
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.mu.Unlock()
+
+	s.parent.Put(object)
+}
+
+// Len returns the number of objects put back to the scope, not including the one shared via [FromContext].
+func (s *Scope[T]) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.free)
+}
+
+// Inspect calls fn for each object put back to the scope, from the oldest to the newest,
+// while holding the lock of the scope. It is intended for tests.
+// Be careful, fn must not call the scope.
+func (s *Scope[T]) Inspect(fn func(object T)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, object := range s.free {
+		fn(object)
+	}
+}
+
+// Close put all objects of the scope back to the parent pool, including the one shared via [FromContext].
+// After Close, Put goes directly to the parent pool. It is safe to call it several times.
+func (s *Scope[T]) Close() {
+	s.mu.Lock()
+	free := s.free
+	s.free = nil
+	s.closed = true
+
+	if s.hasScratch {
+		free = append(free, s.scratch)
+
+		var zero T
+		s.scratch = zero
+		s.hasScratch = false
+	}
+
+	s.mu.Unlock()
+
+	for _, object := range free {
+		s.parent.Put(object)
+	}
+}
@@ -0,0 +1,223 @@
+package xpool_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+type recordingPool struct {
+	gets int
+	puts []*bytes.Buffer
+}
+
+func (p *recordingPool) Get() *bytes.Buffer {
+	p.gets++
+
+	return new(bytes.Buffer)
+}
+
+func (p *recordingPool) Put(object *bytes.Buffer) {
+	p.puts = append(p.puts, object)
+}
+
+func TestScope(t *testing.T) {
+	t.Parallel()
+
+	parent := new(recordingPool)
+
+	ctx, scope := xpool.NewScope[*bytes.Buffer](context.Background(), parent)
+
+	found, ok := xpool.ScopeFromContext[*bytes.Buffer](ctx)
+	require.True(t, ok)
+	require.Same(t, scope, found)
+
+	_, ok = xpool.ScopeFromContext[*bytes.Reader](ctx)
+	assert.False(t, ok, "scopes are per type")
+
+	first := scope.Get()
+	scope.Put(first)
+
+	second := scope.Get()
+	assert.Same(t, first, second, "must prefer the scope free list")
+
+	third := scope.Get()
+	assert.NotSame(t, second, third)
+	assert.Equal(t, 2, parent.gets)
+
+	scope.Put(second)
+	scope.Put(third)
+	assert.Empty(t, parent.puts, "must keep objects until close")
+
+	scope.Close()
+	scope.Close() // idempotent
+	assert.Len(t, parent.puts, 2)
+
+	scope.Put(new(bytes.Buffer))
+	assert.Len(t, parent.puts, 3, "must put to the parent after close")
+}
+
+func TestNewScopeNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.NewScope[*bytes.Buffer](context.Background(), nil)
+	}, "must panic")
+}
+
+func ExampleNewScope() {
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	ctx, scope := xpool.NewScope(context.Background(), pool)
+	defer scope.Close() // at the end of the request
+
+	handle := func(ctx context.Context, name string) {
+		scope, _ := xpool.ScopeFromContext[*bytes.Buffer](ctx)
+
+		buf := scope.Get()
+		defer scope.Put(buf)
+
+		buf.Reset() // objects are not reset by the scope
+		buf.WriteString("hello " + name)
+
+		fmt.Println(buf.String())
+	}
+
+	handle(ctx, "alice")
+	handle(ctx, "bob")
+	// Output:
+	// hello alice
+	// hello bob
+}
+
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	parent := new(recordingPool)
+
+	ctx, scope := xpool.NewScope[*bytes.Buffer](context.Background(), parent)
+
+	first, release := xpool.FromContext[*bytes.Buffer](ctx, parent)
+	release() // no-op
+
+	second, release := xpool.FromContext[*bytes.Buffer](ctx, parent)
+	release()
+
+	assert.Same(t, first, second, "must share the object on the same scope")
+	assert.Equal(t, 1, parent.gets)
+	assert.Empty(t, parent.puts, "must keep the object until the scope is closed")
+
+	scope.Close()
+
+	require.Len(t, parent.puts, 1)
+	assert.Same(t, first, parent.puts[0])
+}
+
+func TestFromContextWithoutScope(t *testing.T) {
+	t.Parallel()
+
+	parent := new(recordingPool)
+
+	object, release := xpool.FromContext[*bytes.Buffer](context.Background(), parent)
+	assert.Equal(t, 1, parent.gets)
+	assert.Empty(t, parent.puts)
+
+	release()
+
+	require.Len(t, parent.puts, 1)
+	assert.Same(t, object, parent.puts[0])
+}
+
+func TestScopeInspect(t *testing.T) {
+	t.Parallel()
+
+	_, scope := xpool.NewScope[*bytes.Buffer](context.Background(), new(recordingPool))
+
+	first, second := scope.Get(), scope.Get()
+	scope.Put(first)
+	scope.Put(second)
+
+	var seen []*bytes.Buffer
+
+	scope.Inspect(func(b *bytes.Buffer) {
+		seen = append(seen, b)
+	})
+
+	require.Len(t, seen, 2)
+	assert.Same(t, first, seen[0])
+	assert.Same(t, second, seen[1])
+	assert.Equal(t, 2, scope.Len())
+}
+
+func TestScopeResets(t *testing.T) {
+	t.Parallel()
+
+	t.Run("resetter", func(t *testing.T) {
+		t.Parallel()
+
+		parent := xpool.NewWithResetter(func() *bytes.Buffer {
+			return new(bytes.Buffer)
+		})
+
+		_, scope := xpool.NewScope(context.Background(), parent)
+		defer scope.Close()
+
+		buf := scope.Get()
+		buf.WriteString("dirty")
+		scope.Put(buf)
+
+		got := scope.Get()
+		require.Same(t, buf, got)
+		assert.Zero(t, got.Len(), "must reset the object on Put")
+	})
+
+	t.Run("ptrpool", func(t *testing.T) {
+		t.Parallel()
+
+		parent := xpool.NewPtrPool[bytes.Buffer]()
+
+		_, scope := xpool.NewScope[*bytes.Buffer](context.Background(), parent)
+		defer scope.Close()
+
+		buf := scope.Get()
+		buf.WriteString("dirty")
+		scope.Put(buf)
+
+		got := scope.Get()
+		require.Same(t, buf, got)
+		assert.Zero(t, got.Len(), "must zero the object on Put")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		var discarded []*bytes.Buffer
+
+		parent := xpool.NewWithCustomResetterE(func() *bytes.Buffer {
+			return new(bytes.Buffer)
+		}, func(*bytes.Buffer) error {
+			return fmt.Errorf("oops")
+		}, xpool.WithOnDiscard(func(object *bytes.Buffer) {
+			discarded = append(discarded, object)
+		}))
+
+		_, scope := xpool.NewScope(context.Background(), parent)
+
+		buf := scope.Get()
+		scope.Put(buf)
+
+		assert.Equal(t, []*bytes.Buffer{buf}, discarded, "must discard the object")
+		assert.Zero(t, scope.Len(), "must not keep the object")
+
+		scope.Close()
+		assert.Len(t, discarded, 1)
+	})
+}
@@ -0,0 +1,94 @@
+// Package sharedpool offers reference-counted handles to objects fetched from a [xpool.Pool],
+// so one object can be shared by several readers and it is put back to the pool only
+// when the last handle is released.
+//
+//	configs := sharedpool.New(xpool.NewWithResetter(func() *Config {
+//	  return new(Config)
+//	}))
+//
+//	handle := configs.Get()
+//	decode(handle.Value())
+//
+//	for _, r := range readers {
+//	  go r.Serve(handle.Clone()) // each reader must call Release
+//	}
+//
+//	handle.Release()
+//
+// The shared object must be treated as read-only while it has more than one handle.
+package sharedpool
+
+import (
+	"sync/atomic"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Pool is a pool of reference-counted handles.
+type Pool[T any] struct {
+	pool xpool.Pool[T]
+}
+
+// New returns a [Pool] of handles backed by the given pool.
+// Will panic if pool is nil.
+func New[T any](pool xpool.Pool[T]) *Pool[T] {
+	if pool == nil {
+		panic("argument 'pool' must not be nil")
+	}
+
+	return &Pool[T]{pool: pool}
+}
+
+// Get fetch one item from the underlying pool and returns the first handle to it.
+func (p *Pool[T]) Get() *Handle[T] {
+	return &Handle[T]{
+		shared: &shared[T]{
+			object: p.pool.Get(),
+			refs:   1,
+			put:    p.pool.Put,
+		},
+	}
+}
+
+type shared[T any] struct {
+	object T
+	refs   int64
+	put    func(object T)
+}
+
+// Handle is a reference to a shared object.
+// It can be safely passed across goroutine boundaries, and Release is idempotent.
+type Handle[T any] struct {
+	shared   *shared[T]
+	released uint32
+}
+
+// Value returns the shared object.
+// The object must not be used after Release.
+func (h *Handle[T]) Value() T {
+	return h.shared.object
+}
+
+// Clone returns a new handle to the same object, that must be released independently.
+// Will panic if the handle was already released.
+func (h *Handle[T]) Clone() *Handle[T] {
+	if atomic.LoadUint32(&h.released) != 0 {
+		panic("sharedpool: clone of a released handle")
+	}
+
+	atomic.AddInt64(&h.shared.refs, 1)
+
+	return &Handle[T]{shared: h.shared}
+}
+
+// Release drops this handle, and put the object back to the pool if it was the last one.
+// Only the first call has effect, it is safe to call it several times.
+func (h *Handle[T]) Release() {
+	if !atomic.CompareAndSwapUint32(&h.released, 0, 1) {
+		return
+	}
+
+	if atomic.AddInt64(&h.shared.refs, -1) == 0 {
+		h.shared.put(h.shared.object)
+	}
+}
@@ -0,0 +1,80 @@
+package sharedpool_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool/sharedpool"
+)
+
+type countingPool struct {
+	mu   sync.Mutex
+	puts []*[]string
+}
+
+func (p *countingPool) Get() *[]string {
+	return new([]string)
+}
+
+func (p *countingPool) Put(object *[]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.puts = append(p.puts, object)
+}
+
+func TestHandle(t *testing.T) {
+	t.Parallel()
+
+	backend := new(countingPool)
+	pool := sharedpool.New[*[]string](backend)
+
+	handle := pool.Get()
+	*handle.Value() = append(*handle.Value(), "config")
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		clone := handle.Clone()
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer clone.Release()
+
+			assert.Equal(t, []string{"config"}, *clone.Value())
+		}()
+	}
+
+	handle.Release()
+	handle.Release() // idempotent
+
+	wg.Wait()
+
+	assert.Len(t, backend.puts, 1, "must put back only once, after the last release")
+	assert.Same(t, handle.Value(), backend.puts[0])
+}
+
+func TestHandleCloneAfterRelease(t *testing.T) {
+	t.Parallel()
+
+	pool := sharedpool.New[*[]string](new(countingPool))
+
+	handle := pool.Get()
+	handle.Release()
+
+	assert.Panics(t, func() {
+		handle.Clone()
+	}, "must panic")
+}
+
+func TestNewNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		sharedpool.New[*[]string](nil)
+	}, "must panic")
+}
@@ -0,0 +1,69 @@
+package xpool
+
+import "sync"
+
+// Singleton memoizes the result of an expensive function, like parse a large schema,
+// computed once on the first Get and shared by all callers. It is safe for concurrent use.
+// If the function panics, every Get panics with the same value.
+type Singleton[T any] struct {
+	once      sync.Once
+	ctor      func() T
+	value     T
+	panicked  bool
+	recovered any
+}
+
+// NewSingleton returns a [Singleton] that calls ctor once, on the first Get.
+// Will panic if ctor is nil.
+func NewSingleton[T any](ctor func() T) *Singleton[T] {
+	if ctor == nil {
+		panic("argument 'ctor' must not be nil")
+	}
+
+	return &Singleton[T]{ctor: ctor}
+}
+
+// Get returns the memoized value, calling the function on the first call.
+func (s *Singleton[T]) Get() T {
+	s.once.Do(s.init)
+
+	if s.panicked {
+		panic(s.recovered)
+	}
+
+	return s.value
+}
+
+func (s *Singleton[T]) init() {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			s.panicked, s.recovered = true, recovered
+		}
+
+		s.ctor = nil // release the closure.
+	}()
+
+	s.value = s.ctor()
+}
+
+// NewWithSharedCtor is an alternative constructor of an [Pool] for a given generic type T,
+// where the expensive part of the construction is shared by all objects: shared is called once,
+// on the first pool miss, see [Singleton], and each object is derived from its result,
+// like a parser that clones a pre-parsed schema.
+// Will panic if shared or derive is nil.
+// The behavior can be customized via [Option].
+func NewWithSharedCtor[S, T any](
+	shared func() S,
+	derive func(shared S) T,
+	opts ...Option[T],
+) Pool[T] {
+	if derive == nil {
+		panic("argument 'derive' must not be nil")
+	}
+
+	singleton := NewSingleton(shared)
+
+	return New(func() T {
+		return derive(singleton.Get())
+	}, opts...)
+}
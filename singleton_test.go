@@ -0,0 +1,90 @@
+package xpool_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+type schema struct {
+	fields []string
+}
+
+type parser struct {
+	schema *schema
+	state  []string
+}
+
+func TestNewWithSharedCtor(t *testing.T) {
+	t.Parallel()
+
+	var parses int32
+
+	pool := xpool.NewWithSharedCtor(func() *schema {
+		atomic.AddInt32(&parses, 1)
+
+		return &schema{fields: []string{"id", "name"}}
+	}, func(s *schema) *parser {
+		return &parser{schema: s}
+	})
+
+	assert.Zero(t, atomic.LoadInt32(&parses), "must be lazy")
+
+	var wg sync.WaitGroup
+
+	parsers := make([]*parser, 8)
+
+	for i := range parsers {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			parsers[i] = pool.Get()
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&parses), "must parse the schema once")
+
+	for _, p := range parsers {
+		assert.Same(t, parsers[0].schema, p.schema, "must share the schema")
+	}
+}
+
+func TestSingletonPanic(t *testing.T) {
+	t.Parallel()
+
+	var calls int
+
+	singleton := xpool.NewSingleton(func() int {
+		calls++
+
+		panic("boom")
+	})
+
+	for i := 0; i < 2; i++ {
+		assert.PanicsWithValue(t, "boom", func() {
+			singleton.Get()
+		})
+	}
+
+	assert.Equal(t, 1, calls, "must call the function once")
+}
+
+func TestNewWithSharedCtorInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.NewSingleton[int](nil)
+	}, "must panic if ctor is nil")
+
+	assert.Panics(t, func() {
+		xpool.NewWithSharedCtor[*schema, *parser](func() *schema { return nil }, nil)
+	}, "must panic if derive is nil")
+}
@@ -0,0 +1,46 @@
+package slicepool
+
+import "github.com/peczenyj/xpool"
+
+// Option is a functional option to customize a slice [Pool].
+type Option func(*options)
+
+type options struct {
+	requested *xpool.Histogram
+	retained  *xpool.Histogram
+}
+
+func buildOptions(opts []Option) *options {
+	o := &options{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithRequestedHistogram records the length requested on each Get, like one created with
+// [xpool.ExponentialBounds](512, 2, 12), to calibrate the size classes.
+// Will panic if histogram is nil.
+func WithRequestedHistogram(histogram *xpool.Histogram) Option {
+	if histogram == nil {
+		panic("argument 'histogram' must not be nil")
+	}
+
+	return func(o *options) {
+		o.requested = histogram
+	}
+}
+
+// WithRetainedHistogram records the capacity of each slice put back to the pool, the dropped slices are not observed.
+// Will panic if histogram is nil.
+func WithRetainedHistogram(histogram *xpool.Histogram) Option {
+	if histogram == nil {
+		panic("argument 'histogram' must not be nil")
+	}
+
+	return func(o *options) {
+		o.retained = histogram
+	}
+}
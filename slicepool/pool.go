@@ -0,0 +1,138 @@
+// Package slicepool offers a pool of slices organized in size classes, powers of two.
+//
+//	pool := slicepool.New[byte](512, 1<<20)
+//
+//	buf := pool.Get(1000) // len(*buf) == 1000, cap(*buf) == 1024
+//	defer pool.Put(buf)
+//
+// The pool stores pointers to slices, to avoid an allocation on each Put.
+//
+// A *Pool[byte] implements the buffer pool interface expected by gRPC-go (mem.BufferPool),
+// so the same pool can serve both your own code and the gRPC stack:
+//
+//	type BufferPool interface {
+//	  Get(length int) *[]byte
+//	  Put(*[]byte)
+//	}
+package slicepool
+
+import (
+	"math/bits"
+
+	"github.com/peczenyj/xpool"
+)
+
+// maxFallback is the number of larger size classes probed by Get when the requested one is empty,
+// so a slice is reused for lengths down to a quarter of its size class, without wasting too much memory.
+const maxFallback = 2
+
+// Pool is a type-safe pool of slices of E, organized in size classes.
+type Pool[E any] struct {
+	classes   []xpool.Pool[*[]E]
+	minShift  int
+	requested *xpool.Histogram
+	retained  *xpool.Histogram
+}
+
+// New returns a [Pool] with size classes from minSize to maxSize, both rounded up to a power of two.
+// Slices larger than maxSize are not pooled.
+// The behavior can be customized via [Option].
+// Will panic if minSize is not positive or if maxSize is less than minSize.
+func New[E any](minSize, maxSize int, opts ...Option) *Pool[E] {
+	if minSize <= 0 || maxSize < minSize {
+		panic("invalid size classes, must be 0 < minSize <= maxSize")
+	}
+
+	minShift, maxShift := ceilLog2(minSize), ceilLog2(maxSize)
+
+	o := buildOptions(opts)
+
+	p := &Pool[E]{
+		classes:   make([]xpool.Pool[*[]E], maxShift-minShift+1),
+		minShift:  minShift,
+		requested: o.requested,
+		retained:  o.retained,
+	}
+
+	for i := range p.classes {
+		// the size classes return nil when empty, so Get can probe the larger ones.
+		p.classes[i] = xpool.New(func() *[]E {
+			return nil
+		})
+	}
+
+	return p
+}
+
+// Get fetch one slice with the given length from the pool. If needed, will create another slice.
+// The capacity is the size class, so it is always greater or equal to length,
+// or exactly length for slices larger than the last size class. If the size class is empty,
+// a slice from one of the next two size classes is reused. The elements are not zeroed.
+// Will panic if length is negative.
+func (p *Pool[E]) Get(length int) *[]E {
+	if length < 0 {
+		panic("negative length")
+	}
+
+	if p.requested != nil {
+		p.requested.Observe(float64(length))
+	}
+
+	i := ceilLog2(length) - p.minShift
+	if i < 0 {
+		i = 0
+	}
+
+	if i >= len(p.classes) {
+		buf := make([]E, length)
+
+		return &buf
+	}
+
+	for j := i; j < len(p.classes) && j <= i+maxFallback; j++ {
+		if buf := p.classes[j].Get(); buf != nil {
+			*buf = (*buf)[:length]
+
+			return buf
+		}
+	}
+
+	buf := make([]E, length, 1<<(p.minShift+i))
+
+	return &buf
+}
+
+// Put return the slice to the pool of the largest size class that fits its capacity.
+// Slices smaller than the first size class, or larger than the last one, are dropped.
+// The elements are not cleared, for element types with pointers consider clear them before Put.
+func (p *Pool[E]) Put(buf *[]E) {
+	if buf == nil {
+		return
+	}
+
+	c := cap(*buf)
+	if c == 0 {
+		return
+	}
+
+	i := bits.Len(uint(c)) - 1 - p.minShift // floor(log2(c))
+	if i < 0 || i >= len(p.classes) {
+		return // drop it
+	}
+
+	*buf = (*buf)[:1<<(p.minShift+i)]
+
+	if p.retained != nil {
+		p.retained.Observe(float64(c))
+	}
+
+	p.classes[i].Put(buf)
+}
+
+func ceilLog2(n int) int {
+	if n <= 1 {
+		return 0
+	}
+
+	return bits.Len(uint(n - 1))
+}
@@ -0,0 +1,140 @@
+package slicepool_test
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/slicepool"
+)
+
+// bufferPool mirrors the interface mem.BufferPool from gRPC-go.
+type bufferPool interface {
+	Get(length int) *[]byte
+	Put(*[]byte)
+}
+
+var _ bufferPool = (*slicepool.Pool[byte])(nil)
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	pool := slicepool.New[byte](512, 64<<10)
+
+	f := func(length uint16) bool {
+		buf := pool.Get(int(length))
+		defer pool.Put(buf)
+
+		if len(*buf) != int(length) || cap(*buf) < int(length) {
+			return false
+		}
+
+		return cap(*buf) >= 512 && cap(*buf)&(cap(*buf)-1) == 0 // power of two
+	}
+
+	err := quick.Check(f, nil)
+	require.NoError(t, err)
+}
+
+func TestPoolOversized(t *testing.T) {
+	t.Parallel()
+
+	pool := slicepool.New[int](8, 64)
+
+	buf := pool.Get(100)
+	assert.Len(t, *buf, 100)
+
+	pool.Put(buf) // must be dropped
+	pool.Put(nil)
+	pool.Put(new([]int))
+
+	small := make([]int, 4)
+	pool.Put(&small) // must be dropped
+}
+
+func TestPoolPutFitsSizeClass(t *testing.T) {
+	t.Parallel()
+
+	pool := slicepool.New[int](8, 64)
+
+	odd := make([]int, 3, 20)
+	pool.Put(&odd)
+
+	assert.Len(t, odd, 16, "must fit the largest size class")
+}
+
+func TestPoolGetFallsBackToLargerSizeClass(t *testing.T) {
+	t.Parallel()
+
+	pool := slicepool.New[int](8, 256)
+
+	for i := 0; i < 100; i++ {
+		large := pool.Get(100) // size class 128
+		pool.Put(large)
+
+		small := pool.Get(20) // size class 32, empty
+		if cap(*small) == 128 {
+			assert.Len(t, *small, 20)
+
+			return
+		}
+
+		pool.Put(small)
+	}
+
+	t.Fatal("the pool never reused the larger slice")
+}
+
+func TestPoolGetSkipsTooLargeSizeClass(t *testing.T) {
+	t.Parallel()
+
+	pool := slicepool.New[int](8, 256)
+
+	large := pool.Get(200) // size class 256
+	pool.Put(large)
+
+	small := pool.Get(20) // size class 32, the next two are 64 and 128
+	assert.Equal(t, 32, cap(*small), "must not reuse a slice 8 times larger")
+	assert.Len(t, *small, 20)
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() { slicepool.New[byte](0, 1) })
+	assert.Panics(t, func() { slicepool.New[byte](2, 1) })
+	assert.Panics(t, func() { slicepool.New[byte](1, 2).Get(-1) })
+}
+
+func TestWithHistograms(t *testing.T) {
+	t.Parallel()
+
+	requested := xpool.NewHistogram(xpool.ExponentialBounds(8, 2, 4)...) // 8, 16, 32, 64
+	retained := xpool.NewHistogram(xpool.ExponentialBounds(8, 2, 4)...)
+
+	pool := slicepool.New[int](8, 64,
+		slicepool.WithRequestedHistogram(requested),
+		slicepool.WithRetainedHistogram(retained),
+	)
+
+	for _, length := range []int{1, 10, 100} {
+		pool.Put(pool.Get(length))
+	}
+
+	assert.Equal(t, []uint64{1, 1, 0, 0, 1}, requested.Snapshot().Counts)
+	small := make([]int, 4)
+	pool.Put(&small) // must be dropped
+
+	assert.Equal(t, []uint64{1, 1, 0, 0, 1}, retained.Snapshot().Counts, "the small slice is not retained")
+
+	assert.PanicsWithValue(t, "argument 'histogram' must not be nil", func() {
+		slicepool.WithRequestedHistogram(nil)
+	})
+
+	assert.PanicsWithValue(t, "argument 'histogram' must not be nil", func() {
+		slicepool.WithRetainedHistogram(nil)
+	})
+}
@@ -0,0 +1,80 @@
+package xpool
+
+import "sync/atomic"
+
+// Stats holds the counters of a [Pool], enabled via [WithStats].
+// The zero value is ready to use and it is safe for concurrent use.
+type Stats struct {
+	gets          uint64
+	puts          uint64
+	news          uint64
+	resetFailures uint64
+	evictions     uint64
+	lost          uint64
+}
+
+// StatsSnapshot is a point-in-time copy of the [Stats] counters.
+type StatsSnapshot struct {
+	// Gets is the number of calls to Get.
+	Gets uint64
+	// Puts is the number of calls to Put.
+	Puts uint64
+	// News is the number of calls to the constructor.
+	News uint64
+	// ResetFailures is the number of times the resetter returned an error.
+	ResetFailures uint64
+	// Evictions is the number of objects reclaimed by the garbage collection while stored on the pool,
+	// instead being reused. It requires [WithEvictionStats].
+	Evictions uint64
+	// Lost is the number of objects checked out from the pool and reclaimed by the garbage collection
+	// without being put back. It requires [WithLostObjects].
+	Lost uint64
+}
+
+// Snapshot returns a copy of the current counters.
+func (s *Stats) Snapshot() StatsSnapshot {
+	return StatsSnapshot{
+		Gets:          atomic.LoadUint64(&s.gets),
+		Puts:          atomic.LoadUint64(&s.puts),
+		News:          atomic.LoadUint64(&s.news),
+		ResetFailures: atomic.LoadUint64(&s.resetFailures),
+		Evictions:     atomic.LoadUint64(&s.evictions),
+		Lost:          atomic.LoadUint64(&s.lost),
+	}
+}
+
+func (s *Stats) incGets() {
+	if s != nil {
+		atomic.AddUint64(&s.gets, 1)
+	}
+}
+
+func (s *Stats) incPuts() {
+	if s != nil {
+		atomic.AddUint64(&s.puts, 1)
+	}
+}
+
+func (s *Stats) incNews() {
+	if s != nil {
+		atomic.AddUint64(&s.news, 1)
+	}
+}
+
+func (s *Stats) incResetFailures() {
+	if s != nil {
+		atomic.AddUint64(&s.resetFailures, 1)
+	}
+}
+
+func (s *Stats) incEvictions() {
+	if s != nil {
+		atomic.AddUint64(&s.evictions, 1)
+	}
+}
+
+func (s *Stats) incLost() {
+	if s != nil {
+		atomic.AddUint64(&s.lost, 1)
+	}
+}
@@ -0,0 +1,39 @@
+package xpool_test
+
+import (
+	"crypto/sha256"
+	"hash"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithStats(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.NewWithResetter(sha256.New, xpool.WithStats[hash.Hash](&stats))
+
+	hasher1 := pool.Get()
+	hasher2 := pool.Get()
+
+	pool.Put(hasher1)
+	pool.Put(hasher2)
+
+	assert.Equal(t, xpool.StatsSnapshot{
+		Gets: 2,
+		Puts: 2,
+		News: 2,
+	}, stats.Snapshot())
+}
+
+func TestWithStatsNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithStats[hash.Hash](nil)
+	}, "must panic")
+}
@@ -0,0 +1,116 @@
+// Package statsexport exports the counters of [xpool.Stats] to the StatsD and go-metrics style sinks,
+// without depending on any client library.
+//
+// Each call to [Exporter.Export] takes a snapshot and sends the deltas since the previous export as counters:
+//
+//	conn, err := net.Dial("udp", "127.0.0.1:8125")
+//	if err != nil {
+//	  return err
+//	}
+//
+//	exporter := statsexport.New("xpool.buffers", &stats, statsexport.NewStatsD(conn))
+//	go exporter.Run(ctx, 10*time.Second)
+//
+// For rcrowley/go-metrics, adapt the registry via [SinkFunc]:
+//
+//	sink := statsexport.SinkFunc(func(name string, delta int64) {
+//	  metrics.GetOrRegisterCounter(name, metrics.DefaultRegistry).Inc(delta)
+//	})
+package statsexport
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Sink receives the counters exported, as deltas since the previous export.
+type Sink interface {
+	Count(name string, delta int64)
+}
+
+// SinkFunc is an adapter to allow the use of ordinary functions as [Sink].
+type SinkFunc func(name string, delta int64)
+
+// Count calls f(name, delta).
+func (f SinkFunc) Count(name string, delta int64) {
+	f(name, delta)
+}
+
+// Exporter sends the counters of a [xpool.Stats] to a [Sink]. It is safe for concurrent use.
+type Exporter struct {
+	prefix string
+	stats  *xpool.Stats
+	sink   Sink
+
+	mu   sync.Mutex
+	last xpool.StatsSnapshot
+}
+
+// New returns an [Exporter] of the stats, the names of the counters are the prefix followed by
+// a dot and the name of the counter: gets, puts, news, reset_failures, evictions and lost.
+// Will panic if stats or sink are nil.
+func New(prefix string, stats *xpool.Stats, sink Sink) *Exporter {
+	if stats == nil {
+		panic("argument 'stats' must not be nil")
+	}
+
+	if sink == nil {
+		panic("argument 'sink' must not be nil")
+	}
+
+	if prefix != "" {
+		prefix += "."
+	}
+
+	return &Exporter{prefix: prefix, stats: stats, sink: sink}
+}
+
+// Export sends the counters that changed since the previous export.
+func (e *Exporter) Export() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	current := e.stats.Snapshot()
+
+	e.count("gets", current.Gets, e.last.Gets)
+	e.count("puts", current.Puts, e.last.Puts)
+	e.count("news", current.News, e.last.News)
+	e.count("reset_failures", current.ResetFailures, e.last.ResetFailures)
+	e.count("evictions", current.Evictions, e.last.Evictions)
+	e.count("lost", current.Lost, e.last.Lost)
+
+	e.last = current
+}
+
+func (e *Exporter) count(name string, current, last uint64) {
+	if current == last {
+		return
+	}
+
+	e.sink.Count(e.prefix+name, int64(current-last))
+}
+
+// Run calls Export on each interval, until the context is done, exporting one last time before return.
+// Will panic if interval is not positive.
+func (e *Exporter) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		panic("argument 'interval' must be positive")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.Export()
+
+			return
+		case <-ticker.C:
+			e.Export()
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package statsexport_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/statsexport"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	deltas map[string]int64
+}
+
+func (s *recordingSink) Count(name string, delta int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.deltas == nil {
+		s.deltas = make(map[string]int64)
+	}
+
+	s.deltas[name] += delta
+}
+
+func (s *recordingSink) snapshot() map[string]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deltas := make(map[string]int64, len(s.deltas))
+	for name, delta := range s.deltas {
+		deltas[name] = delta
+	}
+
+	return deltas
+}
+
+func TestExporter(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStats[*bytes.Buffer](&stats))
+
+	var sent []string
+
+	exporter := statsexport.New("buffers", &stats, statsexport.SinkFunc(func(name string, delta int64) {
+		sent = append(sent, name)
+
+		assert.Positive(t, delta)
+	}))
+
+	pool.Put(pool.Get())
+
+	exporter.Export()
+
+	assert.ElementsMatch(t, []string{"buffers.gets", "buffers.puts", "buffers.news"}, sent)
+
+	sent = nil
+
+	exporter.Export()
+
+	assert.Empty(t, sent, "must send only the counters that changed")
+
+	pool.Get()
+
+	exporter.Export()
+
+	assert.Contains(t, sent, "buffers.gets")
+	assert.NotContains(t, sent, "buffers.puts")
+}
+
+func TestExporterRun(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStats[*bytes.Buffer](&stats))
+
+	sink := new(recordingSink)
+
+	exporter := statsexport.New("", &stats, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		exporter.Run(ctx, time.Millisecond)
+	}()
+
+	for i := 0; i < 3; i++ {
+		pool.Put(pool.Get())
+	}
+
+	cancel()
+	<-done
+
+	deltas := sink.snapshot()
+
+	assert.Equal(t, int64(3), deltas["gets"], "must export the last deltas before return")
+	assert.Equal(t, int64(3), deltas["puts"])
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	sink := new(recordingSink)
+
+	assert.PanicsWithValue(t, "argument 'stats' must not be nil", func() {
+		statsexport.New("", nil, sink)
+	})
+
+	assert.PanicsWithValue(t, "argument 'sink' must not be nil", func() {
+		statsexport.New("", new(xpool.Stats), nil)
+	})
+
+	assert.PanicsWithValue(t, "argument 'interval' must be positive", func() {
+		statsexport.New("", new(xpool.Stats), sink).Run(context.Background(), 0)
+	})
+}
@@ -0,0 +1,34 @@
+package statsexport
+
+import (
+	"io"
+	"strconv"
+)
+
+// StatsD is a [Sink] that writes the counters in the StatsD line protocol, like "xpool.gets:42|c",
+// one write per counter, so each one fits on its own datagram when w is an UDP connection.
+// The write errors are ignored, like the StatsD clients do.
+type StatsD struct {
+	w io.Writer
+}
+
+// NewStatsD returns a [StatsD] sink that writes to w.
+// Will panic if w is nil.
+func NewStatsD(w io.Writer) *StatsD {
+	if w == nil {
+		panic("argument 'w' must not be nil")
+	}
+
+	return &StatsD{w: w}
+}
+
+// Count writes one counter line.
+func (s *StatsD) Count(name string, delta int64) {
+	line := make([]byte, 0, len(name)+24)
+	line = append(line, name...)
+	line = append(line, ':')
+	line = strconv.AppendInt(line, delta, 10)
+	line = append(line, "|c\n"...)
+
+	_, _ = s.w.Write(line)
+}
@@ -0,0 +1,35 @@
+package statsexport_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/statsexport"
+)
+
+func TestStatsD(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stats xpool.Stats
+		out   bytes.Buffer
+	)
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStats[*bytes.Buffer](&stats))
+
+	pool.Get()
+	pool.Get()
+
+	statsexport.New("xpool", &stats, statsexport.NewStatsD(&out)).Export()
+
+	assert.Equal(t, "xpool.gets:2|c\nxpool.news:2|c\n", out.String())
+
+	assert.PanicsWithValue(t, "argument 'w' must not be nil", func() {
+		statsexport.NewStatsD(nil)
+	})
+}
@@ -0,0 +1,33 @@
+package tenantpool
+
+// Option is a functional option to customize a tenant [Pool].
+// It is parameterized on the same generic type K of the tenant keys.
+type Option[K comparable] func(*options[K])
+
+type options[K comparable] struct {
+	quota func(key K) int
+}
+
+func buildOptions[K comparable](opts []Option[K]) *options[K] {
+	o := &options[K]{}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithTenantQuota sets the quota of each tenant, instead the same quota for all of them,
+// like a bigger quota for premium customers. It is called once, when the tenant is created.
+// A quota less or equal to zero means no limit.
+// Will panic if quota is nil.
+func WithTenantQuota[K comparable](quota func(key K) int) Option[K] {
+	if quota == nil {
+		panic("callback 'quota' must not be nil")
+	}
+
+	return func(o *options[K]) {
+		o.quota = quota
+	}
+}
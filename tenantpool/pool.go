@@ -0,0 +1,196 @@
+// Package tenantpool offers a multi-tenant pool: the objects come from a shared store,
+// like a [xpool.Pool], while each tenant key has its own in-flight quota and stats,
+// so one tenant can't starve the others of expensive pooled objects, like codecs.
+//
+//	pool := tenantpool.New[string](store, 4) // up to 4 objects checked out per tenant
+//
+//	codec, err := pool.GetContext(ctx, customerID) // waits if the customer exhausted its quota
+//	if err != nil {
+//	  return err
+//	}
+//	defer pool.Put(customerID, codec)
+//
+// The tenants are created on demand and kept until [Pool.Remove].
+package tenantpool
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/internal/semaphore"
+)
+
+// Pool shares one object store between tenants, each one with its own in-flight quota and stats.
+// It is safe for concurrent use.
+type Pool[K comparable, T any] struct {
+	store   xpool.Pool[T]
+	quota   func(key K) int
+	mu      sync.RWMutex
+	tenants map[K]*tenant
+}
+
+type tenant struct {
+	gets     uint64
+	puts     uint64
+	waits    uint64
+	inFlight semaphore.Semaphore
+}
+
+// Stats is a point-in-time copy of the counters of a tenant.
+type Stats struct {
+	// Gets is the number of objects checked out by the tenant.
+	Gets uint64
+	// Puts is the number of objects put back by the tenant.
+	Puts uint64
+	// Waits is the number of times the tenant had to wait for its quota.
+	Waits uint64
+	// InFlight is the number of objects checked out by the tenant right now, Gets minus Puts.
+	InFlight int
+}
+
+// New returns a [Pool] of objects fetched from store, where each tenant can check out up to quota objects
+// at the same time. A quota less or equal to zero means no limit.
+// The behavior can be customized via [Option].
+// Will panic if store is nil.
+func New[K comparable, T any](store xpool.Pool[T], quota int, opts ...Option[K]) *Pool[K, T] {
+	if store == nil {
+		panic("argument 'store' must not be nil")
+	}
+
+	o := buildOptions(opts)
+
+	p := &Pool[K, T]{
+		store:   store,
+		quota:   o.quota,
+		tenants: make(map[K]*tenant),
+	}
+
+	if p.quota == nil {
+		p.quota = func(K) int { return quota }
+	}
+
+	return p
+}
+
+// Get fetch one object from the store for the tenant. It blocks while the tenant exhausted its quota.
+func (p *Pool[K, T]) Get(key K) T {
+	object, _ := p.GetContext(context.Background(), key)
+
+	return object
+}
+
+// GetContext fetch one object from the store for the tenant, like Get,
+// but it returns an error if the context is done while waiting for the quota.
+func (p *Pool[K, T]) GetContext(ctx context.Context, key K) (T, error) {
+	var zero T
+
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	t := p.tenant(key)
+
+	if !t.inFlight.TryAcquire() {
+		atomic.AddUint64(&t.waits, 1)
+
+		if err := t.inFlight.Acquire(ctx); err != nil {
+			return zero, err
+		}
+	}
+
+	object, err := xpool.GetContext(ctx, p.store)
+	if err != nil {
+		t.inFlight.Release()
+
+		return zero, err
+	}
+
+	atomic.AddUint64(&t.gets, 1)
+
+	return object, nil
+}
+
+// Put return the object to the store, releasing one slot of the tenant quota.
+func (p *Pool[K, T]) Put(key K, object T) {
+	t := p.tenant(key)
+
+	atomic.AddUint64(&t.puts, 1)
+
+	p.store.Put(object)
+	t.inFlight.Release()
+}
+
+// Stats returns the counters of the tenant.
+func (p *Pool[K, T]) Stats(key K) Stats {
+	p.mu.RLock()
+	t, ok := p.tenants[key]
+	p.mu.RUnlock()
+
+	if !ok {
+		return Stats{}
+	}
+
+	stats := Stats{
+		Gets:  atomic.LoadUint64(&t.gets),
+		Puts:  atomic.LoadUint64(&t.puts),
+		Waits: atomic.LoadUint64(&t.waits),
+	}
+
+	if stats.Gets > stats.Puts {
+		stats.InFlight = int(stats.Gets - stats.Puts)
+	}
+
+	return stats
+}
+
+// Remove forgets the tenant, including its counters. Objects checked out by the tenant
+// can still be put back, on a fresh quota.
+func (p *Pool[K, T]) Remove(key K) {
+	p.mu.Lock()
+	delete(p.tenants, key)
+	p.mu.Unlock()
+}
+
+// Tenant returns a view of the pool for the tenant, that implements [xpool.Pool].
+func (p *Pool[K, T]) Tenant(key K) xpool.Pool[T] {
+	return &tenantView[K, T]{pool: p, key: key}
+}
+
+func (p *Pool[K, T]) tenant(key K) *tenant {
+	p.mu.RLock()
+	t, ok := p.tenants[key]
+	p.mu.RUnlock()
+
+	if ok {
+		return t
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if t, ok = p.tenants[key]; !ok {
+		t = &tenant{inFlight: semaphore.New(p.quota(key))}
+		p.tenants[key] = t
+	}
+
+	return t
+}
+
+type tenantView[K comparable, T any] struct {
+	pool *Pool[K, T]
+	key  K
+}
+
+func (v *tenantView[K, T]) Get() T {
+	return v.pool.Get(v.key)
+}
+
+func (v *tenantView[K, T]) GetContext(ctx context.Context) (T, error) {
+	return v.pool.GetContext(ctx, v.key)
+}
+
+func (v *tenantView[K, T]) Put(object T) {
+	v.pool.Put(v.key, object)
+}
@@ -0,0 +1,84 @@
+package tenantpool_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/tenantpool"
+)
+
+func newStore() xpool.Pool[*bytes.Buffer] {
+	return xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	pool := tenantpool.New[string](newStore(), 1)
+
+	first := pool.Get("alice")
+	other := pool.Get("bob") // must not be blocked by alice
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := pool.GetContext(ctx, "alice")
+	require.ErrorIs(t, err, context.DeadlineExceeded, "alice exhausted her quota")
+
+	assert.Equal(t, tenantpool.Stats{Gets: 1, Waits: 1, InFlight: 1}, pool.Stats("alice"))
+
+	pool.Put("alice", first)
+	pool.Put("bob", other)
+
+	second, err := pool.GetContext(context.Background(), "alice")
+	require.NoError(t, err)
+
+	pool.Put("alice", second)
+
+	assert.Equal(t, tenantpool.Stats{Gets: 2, Puts: 2, Waits: 1}, pool.Stats("alice"))
+	assert.Equal(t, tenantpool.Stats{Gets: 1, Puts: 1}, pool.Stats("bob"))
+	assert.Zero(t, pool.Stats("carol"))
+
+	pool.Remove("alice")
+	assert.Zero(t, pool.Stats("alice"))
+}
+
+func TestWithTenantQuota(t *testing.T) {
+	t.Parallel()
+
+	pool := tenantpool.New(newStore(), 1, tenantpool.WithTenantQuota(func(key string) int {
+		if key == "premium" {
+			return 0 // no limit
+		}
+
+		return 1
+	}))
+
+	premium := pool.Tenant("premium")
+
+	for i := 0; i < 10; i++ {
+		premium.Get()
+	}
+
+	assert.Equal(t, 10, pool.Stats("premium").InFlight)
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		tenantpool.New[string, *bytes.Buffer](nil, 1)
+	}, "must panic if store is nil")
+
+	assert.Panics(t, func() {
+		tenantpool.WithTenantQuota[string](nil)
+	}, "must panic if quota is nil")
+}
@@ -0,0 +1,56 @@
+// Package tmplpool renders templates into pooled buffers.
+//
+// [Execute] renders a [html/template.Template], or a [text/template.Template], into a pooled buffer:
+//
+//	data, release, err := tmplpool.Execute(tmpl, page)
+//	if err != nil {
+//	  return err // nothing was written yet, we can still reply with an error page
+//	}
+//	defer release() // data must not be used after release
+//
+//	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+//	w.Write(data)
+//
+// Rendering into a buffer, instead of the [net/http.ResponseWriter], avoids a partial response when the
+// template fails in the middle of the execution.
+package tmplpool
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/bufpool"
+)
+
+// MaxBufferCap is the maximum capacity of the buffers put back to the pool,
+// the buffers used to render bigger templates are dropped, so one huge page does not pin the memory.
+const MaxBufferCap = 1 << 20
+
+var buffers = bufpool.NewBufferPool(MaxBufferCap)
+
+// Template is the common interface of [html/template.Template] and [text/template.Template].
+type Template interface {
+	Execute(w io.Writer, data any) error
+}
+
+// Execute applies the template t to data, rendering into a pooled buffer.
+// The release function put the buffer back to the pool, the returned bytes must not be used after it.
+// On error, the buffer is put back to the pool and the release function is nil.
+func Execute(t Template, data any) ([]byte, func(), error) {
+	buf := buffers.Get()
+
+	if err := t.Execute(buf, data); err != nil {
+		buffers.Put(buf)
+
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), func() { buffers.Put(buf) }, nil
+}
+
+// Pool returns the underlying pool of buffers used by [Execute], for advanced use,
+// like render several templates into the same buffer. The buffers are resetted before put back to the pool.
+func Pool() xpool.Pool[*bytes.Buffer] {
+	return buffers
+}
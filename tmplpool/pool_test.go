@@ -0,0 +1,69 @@
+package tmplpool_test
+
+import (
+	htmltemplate "html/template"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool/tmplpool"
+)
+
+func TestExecute(t *testing.T) {
+	t.Parallel()
+
+	tmpl := htmltemplate.Must(htmltemplate.New("page").Parse(`<p>{{.}}</p>`))
+
+	data, release, err := tmplpool.Execute(tmpl, "<b>")
+	require.NoError(t, err)
+	require.NotNil(t, release)
+
+	defer release()
+
+	assert.Equal(t, "<p>&lt;b&gt;</p>", string(data))
+}
+
+func TestExecuteText(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("page").Parse(`hello {{.Name}}`))
+
+	for _, name := range []string{"foo", "bar"} {
+		data, release, err := tmplpool.Execute(tmpl, struct{ Name string }{name})
+		require.NoError(t, err)
+
+		assert.Equal(t, "hello "+name, string(data))
+
+		release()
+	}
+}
+
+func TestExecuteError(t *testing.T) {
+	t.Parallel()
+
+	tmpl := template.Must(template.New("page").Parse(`partial {{.Missing}}`))
+
+	data, release, err := tmplpool.Execute(tmpl, struct{}{})
+	require.Error(t, err)
+	assert.Nil(t, data)
+	assert.Nil(t, release)
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	header := template.Must(template.New("header").Parse(`<h1>{{.}}</h1>`))
+	body := template.Must(template.New("body").Parse(`<p>{{.}}</p>`))
+
+	pool := tmplpool.Pool()
+
+	buf := pool.Get()
+	defer pool.Put(buf)
+
+	require.NoError(t, header.Execute(buf, "title"))
+	require.NoError(t, body.Execute(buf, "text"))
+
+	assert.Equal(t, "<h1>title</h1><p>text</p>", buf.String())
+}
@@ -0,0 +1,97 @@
+package xpool
+
+import (
+	"sync"
+	"time"
+)
+
+// tracked wraps an object stored on the pool when [WithMaxUses], [WithGeneration] or [WithMaxObjectAge] are used.
+type tracked[T any] struct {
+	object     T
+	uses       int
+	generation uint64
+	created    time.Time
+}
+
+// tracker counts how many times each object was fetched from the pool, the generation
+// and the time where it was created. The objects checked out from the pool are kept on a map,
+// so they can be found on Put.
+type tracker[T any] struct {
+	maxUses    int
+	generation *Generation
+	maxAge     time.Duration
+	clock      Clock
+	checkedOut sync.Map
+}
+
+func newTracker[T any](o *options[T]) *tracker[T] {
+	if o.maxUses <= 0 && o.generation == nil && o.maxObjectAge <= 0 {
+		return nil
+	}
+
+	return &tracker[T]{
+		maxUses:    o.maxUses,
+		generation: o.generation,
+		maxAge:     o.maxObjectAge,
+		clock:      o.getClock(),
+	}
+}
+
+// now returns the creation time of the new objects, only needed by [WithMaxObjectAge].
+func (t *tracker[T]) now() time.Time {
+	if t.maxAge <= 0 {
+		return time.Time{}
+	}
+
+	return t.clock.Now()
+}
+
+// expired tells if the object is older than [WithMaxObjectAge].
+func (t *tracker[T]) expired(entry *tracked[T]) bool {
+	return t.maxAge > 0 && t.clock.Now().Sub(entry.created) >= t.maxAge
+}
+
+// checkOut unwraps the value stored on the pool, if any, and counts a new use.
+// It returns false as current if the object belongs to an old generation, or if it is expired.
+func (t *tracker[T]) checkOut(value any) (object T, current, ok bool) {
+	entry, ok := value.(*tracked[T])
+	if !ok {
+		return object, false, false
+	}
+
+	entry.uses++
+	t.checkedOut.Store(any(entry.object), entry)
+
+	return entry.object, entry.generation == t.generation.current() && !t.expired(entry), true
+}
+
+// checkOutNew counts the first use of an object created by the constructor on the given generation.
+func (t *tracker[T]) checkOutNew(object T, generation uint64, created time.Time) {
+	t.checkedOut.Store(any(object), &tracked[T]{object: object, uses: 1, generation: generation, created: created})
+}
+
+// checkIn returns the value to be stored on the pool, or false if the object must be retired.
+func (t *tracker[T]) checkIn(object T) (any, bool) {
+	value, ok := t.checkedOut.LoadAndDelete(any(object))
+	if !ok {
+		// the object was not fetched from this pool.
+		value = &tracked[T]{object: object, uses: 1, generation: t.generation.current(), created: t.now()}
+	}
+
+	entry, _ := value.(*tracked[T])
+
+	if t.maxUses > 0 && entry.uses >= t.maxUses {
+		return nil, false
+	}
+
+	if entry.generation != t.generation.current() || t.expired(entry) {
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// forget stops tracking an object that will not be put back to the pool.
+func (t *tracker[T]) forget(object T) {
+	t.checkedOut.Delete(any(object))
+}
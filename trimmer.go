@@ -0,0 +1,60 @@
+package xpool
+
+import "context"
+
+// Trimmer decides what to do with the over-grown objects before put them back to the pool,
+// instead drop them outright, see [WithTrimmer].
+type Trimmer[T any] interface {
+	// Trim returns the object to be put back to the pool, possibly shrunk or replaced by a smaller one,
+	// or false to drop it.
+	Trim(object T) (T, bool)
+}
+
+// TrimmerFunc is an adapter to use an ordinary function as a [Trimmer].
+type TrimmerFunc[T any] func(object T) (T, bool)
+
+// Trim calls f(object).
+func (f TrimmerFunc[T]) Trim(object T) (T, bool) {
+	return f(object)
+}
+
+// trimmedPool calls the trimmer before put the objects back to the inner pool, see [WithTrimmer].
+type trimmedPool[T any] struct {
+	pool    basePool[T]
+	trimmer Trimmer[T]
+}
+
+func newTrimmedPool[T any](pool basePool[T], trimmer Trimmer[T]) basePool[T] {
+	if trimmer == nil {
+		return pool
+	}
+
+	return &trimmedPool[T]{pool: pool, trimmer: trimmer}
+}
+
+func (p *trimmedPool[T]) Get() T {
+	return p.pool.Get()
+}
+
+func (p *trimmedPool[T]) GetContext(ctx context.Context) (T, error) {
+	return p.pool.GetContext(ctx)
+}
+
+func (p *trimmedPool[T]) Put(object T) {
+	trimmed, ok := p.trimmer.Trim(object)
+	if !ok {
+		p.pool.Discard(object)
+
+		return
+	}
+
+	p.pool.Put(trimmed)
+}
+
+func (p *trimmedPool[T]) resetOnPut(object T) error {
+	return resetOnPut[T](p.pool, object)
+}
+
+func (p *trimmedPool[T]) Discard(object T) {
+	p.pool.Discard(object)
+}
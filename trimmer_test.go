@@ -0,0 +1,69 @@
+package xpool_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithTrimmer(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*bytes.Buffer
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	},
+		xpool.WithTrimmer[*bytes.Buffer](xpool.TrimmerFunc[*bytes.Buffer](func(b *bytes.Buffer) (*bytes.Buffer, bool) {
+			return b, b.Len() <= 4 // must be called before the resetter.
+		})),
+		xpool.WithOnDiscard(func(b *bytes.Buffer) {
+			discarded = append(discarded, b)
+		}),
+	)
+
+	small := pool.Get()
+	small.WriteString("abc")
+
+	pool.Put(small)
+
+	assert.Zero(t, small.Len(), "must reset the kept objects")
+	assert.Empty(t, discarded)
+
+	large := pool.Get()
+	large.WriteString("payload")
+
+	pool.Put(large)
+
+	assert.Equal(t, "payload", large.String(), "must not reset the dropped objects")
+	assert.Len(t, discarded, 1)
+	assert.Same(t, large, discarded[0])
+}
+
+func TestWithTrimmerReplace(t *testing.T) {
+	t.Parallel()
+
+	replacement := new(bytes.Buffer)
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithTrimmer[*bytes.Buffer](xpool.TrimmerFunc[*bytes.Buffer](func(*bytes.Buffer) (*bytes.Buffer, bool) {
+		return replacement, true
+	})), xpool.WithHotTier[*bytes.Buffer](1)) // the sync.Pool may drop the replacement
+
+	pool.Put(new(bytes.Buffer))
+
+	object := pool.Get()
+	assert.Same(t, replacement, object)
+}
+
+func TestWithTrimmerNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.WithTrimmer[*bytes.Buffer](nil)
+	}, "must panic")
+}
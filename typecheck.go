@@ -0,0 +1,72 @@
+package xpool
+
+import (
+	"reflect"
+	"sync/atomic"
+)
+
+// typeCheck learns the dynamic type of the objects created by the constructor, see [WithTypeCheck].
+type typeCheck[T any] struct {
+	onMismatch func(object T)
+	dynamic    atomic.Value // reflect.Type, set by the first object created.
+}
+
+// learn wraps the constructor, recording the dynamic type of the first object created.
+func (c *typeCheck[T]) learn(ctor func() T) func() T {
+	return func() T {
+		object := ctor()
+
+		if c.dynamic.Load() == nil {
+			if dynamic := reflect.TypeOf(object); dynamic != nil {
+				c.dynamic.Store(dynamic)
+			}
+		}
+
+		return object
+	}
+}
+
+// matches tells if the object has the same dynamic type of the objects created by the constructor.
+// The nil objects, and the objects put back before the first call to the constructor, are not checked.
+func (c *typeCheck[T]) matches(object T) bool {
+	dynamic, _ := c.dynamic.Load().(reflect.Type)
+
+	actual := reflect.TypeOf(object)
+
+	return dynamic == nil || actual == nil || actual == dynamic
+}
+
+// typedPool discards the objects put back with a dynamic type different from the constructor,
+// calling the callback set via [WithOnDiscard], if any.
+type typedPool[T any] struct {
+	basePool[T]
+	check *typeCheck[T]
+}
+
+func newTypedPool[T any](pool basePool[T], o *options[T]) basePool[T] {
+	if o.typeCheck == nil {
+		return pool
+	}
+
+	return &typedPool[T]{
+		basePool: pool,
+		check:    o.typeCheck,
+	}
+}
+
+func (p *typedPool[T]) Put(object T) {
+	if !p.check.matches(object) {
+		p.check.onMismatch(object)
+
+		// discard, instead put back, to release the resources tracked by the pool, like WithMaxInFlight.
+		p.basePool.Discard(object)
+
+		return
+	}
+
+	p.basePool.Put(object)
+}
+
+func (p *typedPool[T]) resetOnPut(object T) error {
+	return resetOnPut[T](p.basePool, object)
+}
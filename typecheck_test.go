@@ -0,0 +1,54 @@
+package xpool_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestWithTypeCheck(t *testing.T) {
+	t.Parallel()
+
+	var mismatches, discarded []io.Reader
+
+	pool := xpool.New(func() io.Reader {
+		return bytes.NewReader(nil)
+	}, xpool.WithTypeCheck(func(r io.Reader) {
+		mismatches = append(mismatches, r)
+	}), xpool.WithOnDiscard(func(r io.Reader) {
+		discarded = append(discarded, r)
+	}))
+
+	reader := pool.Get()
+	require.IsType(t, new(bytes.Reader), reader)
+
+	pool.Put(reader)
+	assert.Empty(t, mismatches)
+
+	other := strings.NewReader("other")
+	pool.Put(other)
+
+	assert.Equal(t, []io.Reader{other}, mismatches)
+	assert.Equal(t, []io.Reader{other}, discarded)
+
+	pool.Put(nil) // nil objects are not checked
+	assert.Len(t, mismatches, 1)
+}
+
+func TestWithTypeCheckInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "callback 'onMismatch' must not be nil", func() {
+		xpool.WithTypeCheck[io.Reader](nil)
+	})
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be an interface type", func() {
+		xpool.WithTypeCheck(func(*bytes.Buffer) {})
+	})
+}
@@ -0,0 +1,78 @@
+package xpool
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Versioned is a [Pool] where the constructor can be replaced at runtime via SetCtor,
+// for instance to follow a configuration reload. The objects created by the previous
+// constructor are lazily retired: they are discarded on their next Get or Put, see [WithOnDiscard].
+// T must be a pointer type, since the pool tracks the checked out objects by their identity, see [WithGeneration].
+type Versioned[T any] struct {
+	pool       Pool[T]
+	ctor       atomic.Value
+	generation Generation
+}
+
+var _ Pool[any] = (*Versioned[any])(nil)
+
+// NewVersioned is the constructor of a [Versioned] pool for a given generic type T.
+// Receives the initial constructor of the type T.
+// The behavior can be customized via [Option].
+// Will panic if ctor is nil, or if T is not a pointer type.
+func NewVersioned[T any](
+	ctor func() T,
+	opts ...Option[T],
+) *Versioned[T] {
+	if ctor == nil {
+		panic("callback 'ctor' must not be nil")
+	}
+
+	v := &Versioned[T]{}
+	v.ctor.Store(ctor)
+
+	v.pool = New(func() T {
+		return v.loadCtor()()
+	}, append(opts[:len(opts):len(opts)], WithGeneration[T](&v.generation))...)
+
+	return v
+}
+
+// SetCtor replaces the constructor, retiring all objects created before.
+// Will panic if ctor is nil.
+func (v *Versioned[T]) SetCtor(ctor func() T) {
+	if ctor == nil {
+		panic("callback 'ctor' must not be nil")
+	}
+
+	v.ctor.Store(ctor)
+	v.generation.Invalidate()
+}
+
+func (v *Versioned[T]) loadCtor() func() T {
+	ctor, _ := v.ctor.Load().(func() T)
+
+	return ctor
+}
+
+// Get fetch one item from object pool, created by the current constructor.
+// If needed, will create another object.
+func (v *Versioned[T]) Get() T {
+	return v.pool.Get()
+}
+
+// GetContext fetch one item from object pool, like Get, see [GetContext].
+func (v *Versioned[T]) GetContext(ctx context.Context) (T, error) {
+	return GetContext(ctx, v.pool)
+}
+
+// Put return the object to the pool, or discard it if it was created by a previous constructor.
+func (v *Versioned[T]) Put(object T) {
+	v.pool.Put(object)
+}
+
+// Discard drops the object instead put it back to the pool, see [Discard].
+func (v *Versioned[T]) Discard(object T) {
+	Discard(v.pool, object)
+}
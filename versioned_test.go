@@ -0,0 +1,74 @@
+package xpool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+type settings struct {
+	version int
+}
+
+func TestVersioned(t *testing.T) {
+	t.Parallel()
+
+	var retired []*settings
+
+	pool := xpool.NewVersioned(func() *settings {
+		return &settings{version: 1}
+	}, xpool.WithOnDiscard(func(s *settings) {
+		retired = append(retired, s)
+	}))
+
+	old := pool.Get()
+	require.Equal(t, 1, old.version)
+
+	pool.SetCtor(func() *settings {
+		return &settings{version: 2}
+	})
+
+	assert.Equal(t, 2, pool.Get().version)
+
+	pool.Put(old) // must be retired
+	require.Len(t, retired, 1)
+	assert.Same(t, old, retired[0])
+
+	assert.Equal(t, 2, pool.Get().version)
+}
+
+func TestVersionedKeepsOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := make([]xpool.Option[*settings], 1, 2)
+	opts[0] = xpool.WithOnDiscard(func(*settings) {})
+
+	xpool.NewVersioned(func() *settings {
+		return new(settings)
+	}, opts...)
+
+	assert.Nil(t, opts[:2][1], "must not write on the backing array of the options")
+}
+
+func TestVersionedNil(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		xpool.NewVersioned[*settings](nil)
+	}, "must panic")
+
+	assert.PanicsWithValue(t, "type parameter 'T' must be a pointer type", func() {
+		xpool.NewVersioned(func() []byte { return nil })
+	}, "must panic if T is not comparable")
+
+	pool := xpool.NewVersioned(func() *settings {
+		return new(settings)
+	})
+
+	assert.Panics(t, func() {
+		pool.SetCtor(nil)
+	}, "must panic")
+}
@@ -0,0 +1,55 @@
+package xpool
+
+import "context"
+
+// View returns a [Pool] of I backed by a pool of T, so one pool of concrete objects can be consumed
+// through different interface-typed views by different subsystems, like a pool of *bytes.Buffer
+// seen as a Pool[io.Writer] and a Pool[io.Reader], sharing the same objects.
+// The conv function converts the objects fetched from the pool, and back converts the objects put back,
+// usually a type assertion. It supports the context cancellation and the discard of the underlying pool,
+// see [GetContext] and [Discard].
+// Will panic if pool, conv or back are nil.
+func View[I, T any](pool Pool[T], conv func(object T) I, back func(object I) T) Pool[I] {
+	if pool == nil {
+		panic("argument 'pool' must not be nil")
+	}
+
+	if conv == nil {
+		panic("callback 'conv' must not be nil")
+	}
+
+	if back == nil {
+		panic("callback 'back' must not be nil")
+	}
+
+	return &viewPool[I, T]{pool: pool, conv: conv, back: back}
+}
+
+type viewPool[I, T any] struct {
+	pool Pool[T]
+	conv func(object T) I
+	back func(object I) T
+}
+
+func (p *viewPool[I, T]) Get() I {
+	return p.conv(p.pool.Get())
+}
+
+func (p *viewPool[I, T]) GetContext(ctx context.Context) (I, error) {
+	object, err := GetContext(ctx, p.pool)
+	if err != nil {
+		var zero I
+
+		return zero, err
+	}
+
+	return p.conv(object), nil
+}
+
+func (p *viewPool[I, T]) Put(object I) {
+	p.pool.Put(p.back(object))
+}
+
+func (p *viewPool[I, T]) Discard(object I) {
+	Discard(p.pool, p.back(object))
+}
@@ -0,0 +1,104 @@
+package xpool_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+)
+
+func TestView(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStats[*bytes.Buffer](&stats))
+
+	writers := xpool.View(pool, func(b *bytes.Buffer) io.Writer {
+		return b
+	}, func(w io.Writer) *bytes.Buffer {
+		b, _ := w.(*bytes.Buffer)
+
+		return b
+	})
+
+	w := writers.Get()
+	require.IsType(t, new(bytes.Buffer), w)
+
+	_, err := io.WriteString(w, "foo")
+	require.NoError(t, err)
+
+	writers.Put(w)
+
+	buf, _ := w.(*bytes.Buffer)
+	assert.Zero(t, buf.Len(), "must be resetted by the underlying pool")
+
+	snapshot := stats.Snapshot()
+	assert.Equal(t, uint64(1), snapshot.Gets)
+	assert.Equal(t, uint64(1), snapshot.Puts)
+}
+
+func TestViewContextAndDiscard(t *testing.T) {
+	t.Parallel()
+
+	var discarded []*bytes.Buffer
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithOnDiscard(func(b *bytes.Buffer) {
+		discarded = append(discarded, b)
+	}))
+
+	readers := xpool.View(pool, func(b *bytes.Buffer) io.Reader {
+		return b
+	}, func(r io.Reader) *bytes.Buffer {
+		b, _ := r.(*bytes.Buffer)
+
+		return b
+	})
+
+	r, err := xpool.GetContext(context.Background(), readers)
+	require.NoError(t, err)
+
+	xpool.Discard(readers, r)
+
+	buf, _ := r.(*bytes.Buffer)
+	assert.Equal(t, []*bytes.Buffer{buf}, discarded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r, err = xpool.GetContext(ctx, readers)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, r)
+}
+
+func TestViewInvalid(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.New(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	})
+
+	conv := func(b *bytes.Buffer) io.Reader { return b }
+	back := func(r io.Reader) *bytes.Buffer { return nil }
+
+	assert.PanicsWithValue(t, "argument 'pool' must not be nil", func() {
+		xpool.View[io.Reader, *bytes.Buffer](nil, conv, back)
+	})
+
+	assert.PanicsWithValue(t, "callback 'conv' must not be nil", func() {
+		xpool.View[io.Reader](pool, nil, back)
+	})
+
+	assert.PanicsWithValue(t, "callback 'back' must not be nil", func() {
+		xpool.View(pool, conv, nil)
+	})
+}
@@ -0,0 +1,98 @@
+// Package workerpool offers a bounded pool of goroutines, where each worker owns
+// a scratch object fetched from a [xpool.Pool] and passed to every task it runs.
+//
+//	buffers := xpool.NewWithResetter(func() *bytes.Buffer {
+//	  return new(bytes.Buffer)
+//	})
+//
+//	wp := workerpool.New(8, buffers)
+//	defer wp.Close()
+//
+//	err := wp.Submit(func(buf *bytes.Buffer) {
+//	  buf.Reset() // the scratch object is reused across tasks
+//	  ...
+//	})
+package workerpool
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/peczenyj/xpool"
+)
+
+// ErrClosed is returned by Submit after Close.
+var ErrClosed = errors.New("workerpool: closed")
+
+// WorkerPool is a bounded pool of goroutines, each one owning a scratch object of type T.
+type WorkerPool[T any] struct {
+	tasks  chan func(scratch T)
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New starts a [WorkerPool] with the given number of workers.
+// Each worker fetch one scratch object from the pool when it starts,
+// and put it back to the pool when the [WorkerPool] is closed.
+// Will panic if workers is not positive.
+func New[T any](workers int, pool xpool.Pool[T]) *WorkerPool[T] {
+	if workers <= 0 {
+		panic("argument 'workers' must be positive")
+	}
+
+	wp := &WorkerPool[T]{
+		tasks: make(chan func(scratch T)),
+	}
+
+	wp.wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go wp.work(pool)
+	}
+
+	return wp
+}
+
+func (wp *WorkerPool[T]) work(pool xpool.Pool[T]) {
+	defer wp.wg.Done()
+
+	scratch := pool.Get()
+	defer pool.Put(scratch)
+
+	for task := range wp.tasks {
+		task(scratch)
+	}
+}
+
+// Submit blocks until one worker accepts the task.
+// The task receives the scratch object of the worker, reused across tasks without reset.
+// Returns [ErrClosed] if the [WorkerPool] was closed.
+func (wp *WorkerPool[T]) Submit(task func(scratch T)) error {
+	wp.mu.RLock()
+	defer wp.mu.RUnlock()
+
+	if wp.closed {
+		return ErrClosed
+	}
+
+	wp.tasks <- task
+
+	return nil
+}
+
+// Close stops accepting new tasks and waits for the running ones.
+// The scratch objects are put back to the pool. It is safe to call it several times.
+func (wp *WorkerPool[T]) Close() {
+	wp.mu.Lock()
+
+	if !wp.closed {
+		wp.closed = true
+
+		close(wp.tasks)
+	}
+
+	wp.mu.Unlock()
+
+	wp.wg.Wait()
+}
@@ -0,0 +1,66 @@
+package workerpool_test
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/workerpool"
+)
+
+func TestWorkerPool(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	buffers := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithStats[*bytes.Buffer](&stats))
+
+	const workers = 4
+
+	wp := workerpool.New(workers, buffers)
+
+	var (
+		mu        sync.Mutex
+		scratches = map[*bytes.Buffer]struct{}{}
+		runs      int64
+	)
+
+	for i := 0; i < 100; i++ {
+		err := wp.Submit(func(buf *bytes.Buffer) {
+			atomic.AddInt64(&runs, 1)
+
+			mu.Lock()
+			scratches[buf] = struct{}{}
+			mu.Unlock()
+		})
+		require.NoError(t, err)
+	}
+
+	wp.Close()
+	wp.Close() // idempotent
+
+	assert.EqualValues(t, 100, atomic.LoadInt64(&runs))
+	assert.LessOrEqual(t, len(scratches), workers)
+
+	snapshot := stats.Snapshot()
+	assert.EqualValues(t, workers, snapshot.Gets)
+	assert.EqualValues(t, workers, snapshot.Puts)
+
+	err := wp.Submit(func(*bytes.Buffer) {})
+	require.ErrorIs(t, err, workerpool.ErrClosed)
+}
+
+func TestNewInvalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Panics(t, func() {
+		workerpool.New(0, xpool.New(func() int { return 0 }))
+	})
+}
@@ -0,0 +1,25 @@
+//go:build !race
+
+package wsbuf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/peczenyj/xpool/slicepool"
+	"github.com/peczenyj/xpool/wsbuf"
+)
+
+// the sync.Pool drops objects at random under the race detector, so the allocations are only measured without it.
+func TestAllocatorAllocs(t *testing.T) {
+	alloc := wsbuf.NewAllocator(slicepool.New[byte](512, 64<<10))
+
+	alloc.Put(alloc.Get(1000)) // warm up
+
+	allocs := testing.AllocsPerRun(100, func() {
+		alloc.Put(alloc.Get(1000))
+	})
+
+	assert.Zero(t, allocs, "must not allocate on a Get and Put cycle")
+}
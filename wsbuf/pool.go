@@ -0,0 +1,100 @@
+// Package wsbuf adapts the xpool byte pools to the buffer interfaces of the popular websocket libraries,
+// so one calibrated pool serves both your own code and the socket layer.
+//
+// [Allocator] exposes a [slicepool.Pool] via the allocator callbacks, like func(size int) []byte
+// and func([]byte), used for the frame read and write buffers:
+//
+//	pool := slicepool.New[byte](4<<10, 1<<20) // shared with the rest of the service
+//	alloc := wsbuf.NewAllocator(pool)
+//
+//	buf := alloc.Get(frameSize)
+//	defer alloc.Put(buf)
+//
+// [BufferPool] implements the interface of the write buffer pool of gorilla/websocket, Get() any and Put(any),
+// where the library stores its own values and Get returns nil when the pool is empty:
+//
+//	upgrader := websocket.Upgrader{
+//	  WriteBufferPool: wsbuf.NewBufferPool(xpool.WithStats[any](&stats)),
+//	}
+package wsbuf
+
+import (
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/slicepool"
+)
+
+// Allocator exposes a [slicepool.Pool] of bytes through the allocator callbacks style, see [Allocator.Get]
+// and [Allocator.Put], that can be used as method values.
+type Allocator struct {
+	pool    *slicepool.Pool[byte]
+	headers xpool.Pool[*[]byte] // the empty slice headers, so Put does not allocate one for the pool.
+}
+
+// NewAllocator returns an [Allocator] backed by the given pool.
+// Will panic if pool is nil.
+func NewAllocator(pool *slicepool.Pool[byte]) *Allocator {
+	if pool == nil {
+		panic("argument 'pool' must not be nil")
+	}
+
+	return &Allocator{
+		pool: pool,
+		headers: xpool.New(func() *[]byte {
+			return new([]byte)
+		}),
+	}
+}
+
+// Get fetch one buffer with the given length from the pool, see [slicepool.Pool.Get].
+// The capacity may be greater than size, and the bytes are not zeroed.
+func (a *Allocator) Get(size int) []byte {
+	header := a.pool.Get(size)
+
+	buf := *header
+	*header = nil
+	a.headers.Put(header)
+
+	return buf
+}
+
+// Put return the buffer to the pool, see [slicepool.Pool.Put].
+// The buffer must not be used after Put.
+func (a *Allocator) Put(buf []byte) {
+	if cap(buf) == 0 {
+		return
+	}
+
+	header := a.headers.Get()
+	*header = buf
+	a.pool.Put(header)
+}
+
+// BufferPool is a pool of arbitrary values that implements the interface Get() any and Put(any),
+// like the write buffer pool of gorilla/websocket, backed by a [xpool.Pool].
+// Get returns nil when the pool is empty, the caller creates its own value.
+type BufferPool struct {
+	pool xpool.Pool[any]
+}
+
+// NewBufferPool returns a [BufferPool]. The calls to the constructor, see [xpool.Stats],
+// are the misses, when Get returns nil.
+// The behavior can be customized via [xpool.Option].
+func NewBufferPool(opts ...xpool.Option[any]) *BufferPool {
+	return &BufferPool{
+		pool: xpool.New(func() any { return nil }, opts...),
+	}
+}
+
+// Get fetch one value from the pool, or nil if the pool is empty.
+func (p *BufferPool) Get() any {
+	return p.pool.Get()
+}
+
+// Put return the value to the pool. Ignores nil values.
+func (p *BufferPool) Put(value any) {
+	if value == nil {
+		return
+	}
+
+	p.pool.Put(value)
+}
@@ -0,0 +1,66 @@
+package wsbuf_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/slicepool"
+	"github.com/peczenyj/xpool/wsbuf"
+)
+
+// bufferPool mirrors the interface websocket.BufferPool from gorilla/websocket.
+type bufferPool interface {
+	Get() interface{}
+	Put(interface{})
+}
+
+var _ bufferPool = (*wsbuf.BufferPool)(nil)
+
+func TestAllocator(t *testing.T) {
+	t.Parallel()
+
+	alloc := wsbuf.NewAllocator(slicepool.New[byte](512, 64<<10))
+
+	var (
+		get func(size int) []byte = alloc.Get
+		put func(buf []byte)      = alloc.Put
+	)
+
+	buf := get(1000)
+	assert.Len(t, buf, 1000)
+	assert.Equal(t, 1024, cap(buf))
+
+	put(buf)
+	put(nil)
+}
+
+func TestNewAllocatorNil(t *testing.T) {
+	t.Parallel()
+
+	assert.PanicsWithValue(t, "argument 'pool' must not be nil", func() {
+		wsbuf.NewAllocator(nil)
+	})
+}
+
+func TestBufferPool(t *testing.T) {
+	t.Parallel()
+
+	var stats xpool.Stats
+
+	pool := wsbuf.NewBufferPool(xpool.WithStats[any](&stats),
+		xpool.WithHotTier[any](1), // the sync.Pool may drop the value
+	)
+
+	assert.Nil(t, pool.Get(), "must be nil when empty")
+	assert.Equal(t, uint64(1), stats.Snapshot().News)
+
+	value := &struct{ buf []byte }{buf: make([]byte, 16)}
+
+	pool.Put(value)
+	pool.Put(nil)
+
+	require.Same(t, value, pool.Get())
+}
@@ -0,0 +1,132 @@
+package xpooltest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/peczenyj/xpool"
+)
+
+var _ xpool.Clock = (*Clock)(nil)
+
+// Clock is a fake [xpool.Clock], where the time only moves via Advance.
+// It is safe for concurrent use.
+//
+//	clock := xpooltest.NewClock(time.Now())
+//	pool := ring.New(8, ctor, ring.WithIdleTimeout[*Object](time.Minute), ring.WithClock[*Object](clock))
+//
+//	clock.Advance(2 * time.Minute) // fires the idle eviction
+type Clock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*timer
+}
+
+// NewClock returns a fake [Clock] starting at now.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the current fake time.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// NewTimer creates a timer that fires when the fake time reaches now plus d.
+func (c *Clock) NewTimer(d time.Duration) xpool.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timer{clock: c, c: make(chan time.Time, 1)}
+	t.resetLocked(d)
+
+	return t
+}
+
+// Advance moves the fake time forward by d, firing the expired timers.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.timers[:0]
+
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+
+			continue
+		}
+
+		select {
+		case t.c <- c.now:
+		default: // like a time.Timer, the channel has a buffer of one.
+		}
+	}
+
+	c.timers = pending
+}
+
+// activeTimers returns the number of timers that did not fire yet.
+func (c *Clock) activeTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.timers)
+}
+
+// WaitTimers blocks until there are at least n active timers, so Advance does not race with
+// a background goroutine that is about to create or reset its timer.
+func (c *Clock) WaitTimers(n int) {
+	for c.activeTimers() < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type timer struct {
+	clock    *Clock
+	c        chan time.Time
+	deadline time.Time
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	return t.stopLocked()
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.stopLocked()
+	t.resetLocked(d)
+
+	return active
+}
+
+func (t *timer) stopLocked() bool {
+	for i, other := range t.clock.timers {
+		if other == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *timer) resetLocked(d time.Duration) {
+	t.deadline = t.clock.now.Add(d)
+	t.clock.timers = append(t.clock.timers, t)
+}
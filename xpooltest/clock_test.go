@@ -0,0 +1,51 @@
+package xpooltest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+func TestClock(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := xpooltest.NewClock(start)
+
+	timer := clock.NewTimer(time.Minute)
+
+	clock.Advance(59 * time.Second)
+
+	select {
+	case <-timer.C():
+		t.Fatal("must not fire before the deadline")
+	default:
+	}
+
+	clock.Advance(time.Second)
+
+	if now := <-timer.C(); !now.Equal(start.Add(time.Minute)) {
+		t.Errorf("unexpected time %v", now)
+	}
+
+	if timer.Stop() {
+		t.Error("a fired timer must not be active")
+	}
+
+	if timer.Reset(time.Second); !timer.Stop() {
+		t.Error("a reset timer must be active")
+	}
+
+	clock.Advance(time.Hour)
+
+	select {
+	case <-timer.C():
+		t.Fatal("a stopped timer must not fire")
+	default:
+	}
+
+	if got := clock.Now(); !got.Equal(start.Add(time.Hour + time.Minute)) {
+		t.Errorf("unexpected now %v", got)
+	}
+}
@@ -0,0 +1,169 @@
+// Package xpooltest offers helpers to test pools and the code that uses them.
+//
+// The [Harness] exercises a pool with a program of Get, Put and Discard operations,
+// running on several goroutines, and checks the invariants that every pool must respect:
+//   - an object is never handed out twice at the same time, including the discarded ones.
+//   - an object fetched from the pool was reset, when the pool is expected to reset it.
+//   - the counters of [xpool.Stats] are consistent with the operations, when enabled.
+//
+// It is designed to be used from native Go fuzz targets:
+//
+//	func FuzzMyPool(f *testing.F) {
+//	  h := xpooltest.Harness{
+//	    NewPool: func(ctor func() *xpooltest.Object, stats *xpool.Stats) xpool.Pool[*xpooltest.Object] {
+//	      return NewMyPool(ctor)
+//	    },
+//	    Resets: true,
+//	  }
+//
+//	  f.Add([]byte{0, 0, 1, 2})
+//	  f.Fuzz(h.Run)
+//	}
+//
+// [AssertResetZeroes] checks, with one line, if the resetter of a pool really resets the objects,
+// and the fake [Clock] drives the time-based features, like the idle eviction, without sleep.
+package xpooltest
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/peczenyj/xpool"
+)
+
+// Object is the object stored on the pools exercised by the [Harness].
+// It implements [xpool.Resetter].
+type Object struct {
+	inUse int32
+	dirty int32
+}
+
+// Reset marks the object as clean.
+func (o *Object) Reset() {
+	atomic.StoreInt32(&o.dirty, 0)
+}
+
+const (
+	opGet = iota
+	opPut
+	opDiscard
+	numOps
+)
+
+// Harness exercises a pool with a program of operations, checking its invariants.
+type Harness struct {
+	// NewPool builds the pool under test, with the given constructor.
+	// If Stats is true, the pool must update the given stats, see [xpool.WithStats].
+	NewPool func(ctor func() *Object, stats *xpool.Stats) xpool.Pool[*Object]
+
+	// Resets tells if the pool must reset the objects, calling Reset before reuse them.
+	Resets bool
+
+	// Stats tells if the pool must update the stats.
+	Stats bool
+
+	// Goroutines is the number of goroutines running the program, by default 4.
+	Goroutines int
+}
+
+// Run builds a pool and runs the program: each byte is an operation, Get, Put or Discard,
+// on the objects held by one of the goroutines. At the end, all objects are put back.
+func (h Harness) Run(t *testing.T, program []byte) {
+	t.Helper()
+
+	goroutines := h.Goroutines
+	if goroutines <= 0 {
+		goroutines = 4
+	}
+
+	var (
+		stats   xpool.Stats
+		created uint64
+		gets    uint64
+		puts    uint64
+		wg      sync.WaitGroup
+	)
+
+	pool := h.NewPool(func() *Object {
+		atomic.AddUint64(&created, 1)
+
+		return new(Object)
+	}, &stats)
+
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			var held []*Object
+
+			for i := g; i < len(program); i += goroutines {
+				op := program[i]
+
+				switch op % numOps {
+				case opGet:
+					object := pool.Get()
+					atomic.AddUint64(&gets, 1)
+
+					if !atomic.CompareAndSwapInt32(&object.inUse, 0, 1) {
+						t.Errorf("object %p handed out twice", object)
+
+						continue
+					}
+
+					if h.Resets && atomic.LoadInt32(&object.dirty) != 0 {
+						t.Errorf("object %p was not reset", object)
+					}
+
+					atomic.StoreInt32(&object.dirty, 1)
+
+					held = append(held, object)
+				case opPut, opDiscard:
+					if len(held) == 0 {
+						continue
+					}
+
+					j := int(op/numOps) % len(held)
+					object := held[j]
+					held = append(held[:j], held[j+1:]...)
+
+					if op%numOps == opDiscard {
+						// the object is still in use, the pool must never hand it out again.
+						xpool.Discard(pool, object)
+
+						continue
+					}
+
+					atomic.StoreInt32(&object.inUse, 0)
+					pool.Put(object)
+					atomic.AddUint64(&puts, 1)
+				}
+			}
+
+			for _, object := range held {
+				atomic.StoreInt32(&object.inUse, 0)
+				pool.Put(object)
+				atomic.AddUint64(&puts, 1)
+			}
+		}(g)
+	}
+
+	wg.Wait()
+
+	if !h.Stats {
+		return
+	}
+
+	want := xpool.StatsSnapshot{
+		Gets:          gets,
+		Puts:          puts,
+		News:          created,
+		ResetFailures: 0,
+	}
+
+	if got := stats.Snapshot(); got != want {
+		t.Errorf("inconsistent stats, got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package xpooltest_test
+
+import (
+	"testing"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/monadic"
+	"github.com/peczenyj/xpool/ring"
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+type object = xpooltest.Object
+
+func fuzz(f *testing.F, h xpooltest.Harness) {
+	f.Helper()
+
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0, 0, 1, 1, 1, 1})
+	f.Add([]byte{0, 0, 0, 0, 2, 2, 2, 2, 0, 0, 0, 0})
+	f.Add([]byte{0, 3, 0, 1, 0, 4, 5, 2, 0, 0, 7, 8, 0, 1, 0, 2})
+
+	f.Fuzz(h.Run)
+}
+
+func FuzzNew(f *testing.F) {
+	fuzz(f, xpooltest.Harness{
+		NewPool: func(ctor func() *object, stats *xpool.Stats) xpool.Pool[*object] {
+			return xpool.New(ctor, xpool.WithStats[*object](stats))
+		},
+		Stats: true,
+	})
+}
+
+func FuzzNewWithResetter(f *testing.F) {
+	fuzz(f, xpooltest.Harness{
+		NewPool: func(ctor func() *object, stats *xpool.Stats) xpool.Pool[*object] {
+			return xpool.NewWithResetter(ctor, xpool.WithStats[*object](stats))
+		},
+		Resets: true,
+		Stats:  true,
+	})
+}
+
+func FuzzNewWithOptions(f *testing.F) {
+	fuzz(f, xpooltest.Harness{
+		NewPool: func(ctor func() *object, stats *xpool.Stats) xpool.Pool[*object] {
+			return xpool.NewWithResetter(ctor,
+				xpool.WithStats[*object](stats),
+				xpool.WithHotTier[*object](2),
+				xpool.WithMaxUses[*object](3),
+				xpool.WithGeneration[*object](new(xpool.Generation)),
+			)
+		},
+		Resets: true,
+		Stats:  true,
+	})
+}
+
+func FuzzRing(f *testing.F) {
+	fuzz(f, xpooltest.Harness{
+		NewPool: func(ctor func() *object, _ *xpool.Stats) xpool.Pool[*object] {
+			return ring.New(4, ctor, ring.WithResetter(func(o *object) error {
+				o.Reset()
+
+				return nil
+			}))
+		},
+		Resets: true,
+	})
+}
+
+// monadicPool adapts a monadic pool without state.
+type monadicPool struct {
+	monadic.Pool[struct{}, *object]
+}
+
+func (p monadicPool) Get() *object {
+	return p.Pool.Get(struct{}{})
+}
+
+func FuzzMonadic(f *testing.F) {
+	fuzz(f, xpooltest.Harness{
+		NewPool: func(ctor func() *object, _ *xpool.Stats) xpool.Pool[*object] {
+			return monadicPool{monadic.NewWithCustomResetter(ctor, func(o *object, _ struct{}) {
+				o.Reset()
+			})}
+		},
+		Resets: true,
+	})
+}
@@ -0,0 +1,118 @@
+package xpooltest
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/peczenyj/xpool"
+)
+
+// resetAttempts bounds the Get, mutate and Put cycles of [AssertResetZeroes].
+const resetAttempts = 100
+
+// AssertResetZeroes gets an object from the pool, mutates it, puts it back, gets it again,
+// and verifies via reflection that all fields, including the unexported and the nested ones,
+// were reset to their zero values. Empty slices and maps are accepted, so the resetter may
+// keep the capacity for reuse, like [bytes.Buffer.Reset].
+//
+// If T is a pointer type and the pool drops the object, like the [sync.Pool] may do, mostly under
+// the race detector, the cycle is retried until the same object comes back. The test fails if it
+// never does, like on a disabled pool: use [xpool.WithHotTier] on the pool under test to keep the object.
+func AssertResetZeroes[T any](t testing.TB, pool xpool.Pool[T], mutate func(T)) {
+	t.Helper()
+
+	for i := 0; i < resetAttempts; i++ {
+		object := pool.Get()
+		mutate(object)
+		pool.Put(object)
+
+		again := pool.Get()
+
+		if id, ok := pointerOf(object); ok {
+			if againID, _ := pointerOf(again); againID != id {
+				pool.Put(again)
+
+				continue
+			}
+		}
+
+		visited := make(map[uintptr]bool)
+
+		for _, path := range nonZeroPaths(reflect.ValueOf(&again).Elem(), "object", visited) {
+			t.Errorf("%s was not reset", path)
+		}
+
+		pool.Put(again)
+
+		return
+	}
+
+	t.Fatalf("the pool never returned the object put back after %d attempts, "+
+		"use xpool.WithHotTier on the pool under test to keep it", resetAttempts)
+}
+
+func pointerOf[T any](object T) (uintptr, bool) {
+	value := reflect.ValueOf(&object).Elem()
+
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		return value.Pointer(), true
+	case reflect.Interface:
+		if value.IsNil() {
+			return 0, false
+		}
+
+		return pointerOf(value.Elem().Interface())
+	default:
+		return 0, false
+	}
+}
+
+// nonZeroPaths returns the paths of the values that are not zero, following pointers and interfaces.
+func nonZeroPaths(value reflect.Value, path string, visited map[uintptr]bool) []string {
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() || visited[value.Pointer()] {
+			return nil
+		}
+
+		visited[value.Pointer()] = true
+
+		return nonZeroPaths(value.Elem(), path, visited)
+	case reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+
+		return nonZeroPaths(value.Elem(), path, visited)
+	case reflect.Struct:
+		var paths []string
+
+		for i := 0; i < value.NumField(); i++ {
+			paths = append(paths, nonZeroPaths(value.Field(i), path+"."+value.Type().Field(i).Name, visited)...)
+		}
+
+		return paths
+	case reflect.Array:
+		var paths []string
+
+		for i := 0; i < value.Len(); i++ {
+			paths = append(paths, nonZeroPaths(value.Index(i), path+"["+strconv.Itoa(i)+"]", visited)...)
+		}
+
+		return paths
+	case reflect.Slice, reflect.Map:
+		if value.Len() == 0 {
+			return nil
+		}
+
+		return []string{path}
+	default:
+		if value.IsZero() {
+			return nil
+		}
+
+		return []string{path}
+	}
+}
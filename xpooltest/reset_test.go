@@ -0,0 +1,133 @@
+package xpooltest_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/peczenyj/xpool"
+	"github.com/peczenyj/xpool/xpooltest"
+)
+
+type nested struct {
+	names []string
+	next  *nested
+}
+
+type record struct {
+	id     int
+	tags   map[string]string
+	nested nested
+	parent *record
+	values [2]float64
+}
+
+func (r *record) Reset() {
+	r.id = 0
+	r.values = [2]float64{}
+	r.nested.names = r.nested.names[:0]
+	r.nested.next = nil
+	r.parent = nil
+
+	for k := range r.tags {
+		delete(r.tags, k)
+	}
+}
+
+func TestAssertResetZeroes(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *record {
+		return &record{tags: map[string]string{}}
+	}, xpool.WithHotTier[*record](1)) // the sync.Pool may drop the object
+
+	xpooltest.AssertResetZeroes(t, pool, func(r *record) {
+		r.id = 42
+		r.tags["k"] = "v"
+		r.nested.names = append(r.nested.names, "a", "b")
+		r.nested.next = &nested{}
+		r.parent = r // cycle
+		r.values[1] = 3.14
+	})
+}
+
+func TestAssertResetZeroesBuffer(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *bytes.Buffer {
+		return new(bytes.Buffer)
+	}, xpool.WithHotTier[*bytes.Buffer](1)) // the sync.Pool may drop the object
+
+	xpooltest.AssertResetZeroes(t, pool, func(b *bytes.Buffer) {
+		b.WriteString("payload")
+	})
+}
+
+func TestAssertResetZeroesSyncPool(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *record {
+		return &record{tags: map[string]string{}}
+	}) // the sync.Pool may drop the object, the cycle is retried
+
+	xpooltest.AssertResetZeroes(t, pool, func(r *record) {
+		r.id = 42
+		r.tags["k"] = "v"
+	})
+}
+
+func TestAssertResetZeroesNeverReused(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.NewWithResetter(func() *record {
+		return &record{tags: map[string]string{}}
+	}, xpool.WithDisabled[*record](true))
+
+	mock := &mockT{TB: t}
+
+	xpooltest.AssertResetZeroes[*record](mock, pool, func(r *record) {
+		r.id = 42
+	})
+
+	if mock.fatal == "" || len(mock.errors) != 0 {
+		t.Errorf("must fail instead skip, got fatal %q and errors %q", mock.fatal, mock.errors)
+	}
+}
+
+func TestAssertResetZeroesFailure(t *testing.T) {
+	t.Parallel()
+
+	pool := xpool.New(func() *record {
+		return &record{tags: map[string]string{}}
+	}, xpool.WithHotTier[*record](1)) // without resetter, the sync.Pool may drop the object
+
+	mock := &mockT{TB: t}
+
+	xpooltest.AssertResetZeroes[*record](mock, pool, func(r *record) {
+		r.id = 42
+		r.nested.names = append(r.nested.names, "a")
+	})
+
+	want := []string{"object.id was not reset", "object.nested.names was not reset"}
+	if len(mock.errors) != len(want) || mock.errors[0] != want[0] || mock.errors[1] != want[1] {
+		t.Errorf("unexpected errors %q, want %q", mock.errors, want)
+	}
+}
+
+// mockT records the errors, instead failing the test.
+type mockT struct {
+	testing.TB
+
+	errors []string
+	fatal  string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Errorf(format string, args ...any) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func (m *mockT) Fatalf(format string, args ...any) {
+	m.fatal = fmt.Sprintf(format, args...)
+}